@@ -0,0 +1,2428 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: events/v1/events.proto
+
+package eventsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EventType int32
+
+const (
+	EventType_EVENT_TYPE_UNSPECIFIED EventType = 0
+	EventType_EVENT_TYPE_PAGE_VIEW   EventType = 1
+	EventType_EVENT_TYPE_CLICK       EventType = 2
+	EventType_EVENT_TYPE_PURCHASE    EventType = 3
+	EventType_EVENT_TYPE_SIGNUP      EventType = 4
+	EventType_EVENT_TYPE_CUSTOM      EventType = 5
+)
+
+// Enum value maps for EventType.
+var (
+	EventType_name = map[int32]string{
+		0: "EVENT_TYPE_UNSPECIFIED",
+		1: "EVENT_TYPE_PAGE_VIEW",
+		2: "EVENT_TYPE_CLICK",
+		3: "EVENT_TYPE_PURCHASE",
+		4: "EVENT_TYPE_SIGNUP",
+		5: "EVENT_TYPE_CUSTOM",
+	}
+	EventType_value = map[string]int32{
+		"EVENT_TYPE_UNSPECIFIED": 0,
+		"EVENT_TYPE_PAGE_VIEW":   1,
+		"EVENT_TYPE_CLICK":       2,
+		"EVENT_TYPE_PURCHASE":    3,
+		"EVENT_TYPE_SIGNUP":      4,
+		"EVENT_TYPE_CUSTOM":      5,
+	}
+)
+
+func (x EventType) Enum() *EventType {
+	p := new(EventType)
+	*p = x
+	return p
+}
+
+func (x EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_events_v1_events_proto_enumTypes[0].Descriptor()
+}
+
+func (EventType) Type() protoreflect.EnumType {
+	return &file_events_v1_events_proto_enumTypes[0]
+}
+
+func (x EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventType.Descriptor instead.
+func (EventType) EnumDescriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	EventType     EventType              `protobuf:"varint,3,opt,name=event_type,json=eventType,proto3,enum=events.v1.EventType" json:"event_type,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Properties    *EventProperties       `protobuf:"bytes,5,opt,name=properties,proto3" json:"properties,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_events_v1_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *Event) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Event) GetEventType() EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetProperties() *EventProperties {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+type EventProperties struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          string                 `protobuf:"bytes,1,opt,name=page,proto3" json:"page,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Email         string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Link          string                 `protobuf:"bytes,5,opt,name=link,proto3" json:"link,omitempty"`
+	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	Currency      string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	Name          string                 `protobuf:"bytes,8,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventProperties) Reset() {
+	*x = EventProperties{}
+	mi := &file_events_v1_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventProperties) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventProperties) ProtoMessage() {}
+
+func (x *EventProperties) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventProperties.ProtoReflect.Descriptor instead.
+func (*EventProperties) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EventProperties) GetPage() string {
+	if x != nil {
+		return x.Page
+	}
+	return ""
+}
+
+func (x *EventProperties) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *EventProperties) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *EventProperties) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *EventProperties) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *EventProperties) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *EventProperties) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *EventProperties) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type EventFilter struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         *string                `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	EventType      EventType              `protobuf:"varint,2,opt,name=event_type,json=eventType,proto3,enum=events.v1.EventType" json:"event_type,omitempty"`
+	StartTimestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start_timestamp,json=startTimestamp,proto3" json:"start_timestamp,omitempty"`
+	EndTimestamp   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=end_timestamp,json=endTimestamp,proto3" json:"end_timestamp,omitempty"`
+	Properties     []*PropertyPredicate   `protobuf:"bytes,5,rep,name=properties,proto3" json:"properties,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EventFilter) Reset() {
+	*x = EventFilter{}
+	mi := &file_events_v1_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventFilter) ProtoMessage() {}
+
+func (x *EventFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventFilter.ProtoReflect.Descriptor instead.
+func (*EventFilter) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *EventFilter) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *EventFilter) GetEventType() EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return EventType_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *EventFilter) GetStartTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTimestamp
+	}
+	return nil
+}
+
+func (x *EventFilter) GetEndTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTimestamp
+	}
+	return nil
+}
+
+func (x *EventFilter) GetProperties() []*PropertyPredicate {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+// PropertyPredicate matches a property such as page or amount. op is eq,
+// ne, gt, gte, lt, lte, contains or prefix, and defaults to eq.
+type PropertyPredicate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Op            string                 `protobuf:"bytes,2,opt,name=op,proto3" json:"op,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyPredicate) Reset() {
+	*x = PropertyPredicate{}
+	mi := &file_events_v1_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyPredicate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyPredicate) ProtoMessage() {}
+
+func (x *PropertyPredicate) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyPredicate.ProtoReflect.Descriptor instead.
+func (*PropertyPredicate) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PropertyPredicate) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *PropertyPredicate) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *PropertyPredicate) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type CreateEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventsRequest) Reset() {
+	*x = CreateEventsRequest{}
+	mi := &file_events_v1_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventsRequest) ProtoMessage() {}
+
+func (x *CreateEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventsRequest.ProtoReflect.Descriptor instead.
+func (*CreateEventsRequest) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateEventsRequest) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type CreateEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*CreateEventResult   `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventsResponse) Reset() {
+	*x = CreateEventsResponse{}
+	mi := &file_events_v1_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventsResponse) ProtoMessage() {}
+
+func (x *CreateEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventsResponse.ProtoReflect.Descriptor instead.
+func (*CreateEventsResponse) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateEventsResponse) GetResults() []*CreateEventResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// CreateEventResult has the stored event, with created false if it was a
+// duplicate of one already stored, or the reason it was rejected.
+type CreateEventResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Created       bool                   `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventResult) Reset() {
+	*x = CreateEventResult{}
+	mi := &file_events_v1_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventResult) ProtoMessage() {}
+
+func (x *CreateEventResult) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventResult.ProtoReflect.Descriptor instead.
+func (*CreateEventResult) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateEventResult) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *CreateEventResult) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+func (x *CreateEventResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventRequest) Reset() {
+	*x = GetEventRequest{}
+	mi := &file_events_v1_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventRequest) ProtoMessage() {}
+
+func (x *GetEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventRequest.ProtoReflect.Descriptor instead.
+func (*GetEventRequest) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetEventRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+type GetEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventResponse) Reset() {
+	*x = GetEventResponse{}
+	mi := &file_events_v1_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventResponse) ProtoMessage() {}
+
+func (x *GetEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventResponse.ProtoReflect.Descriptor instead.
+func (*GetEventResponse) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetEventResponse) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type QueryEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filter        *EventFilter           `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryEventsRequest) Reset() {
+	*x = QueryEventsRequest{}
+	mi := &file_events_v1_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryEventsRequest) ProtoMessage() {}
+
+func (x *QueryEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryEventsRequest.ProtoReflect.Descriptor instead.
+func (*QueryEventsRequest) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *QueryEventsRequest) GetFilter() *EventFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type QueryEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryEventsResponse) Reset() {
+	*x = QueryEventsResponse{}
+	mi := &file_events_v1_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryEventsResponse) ProtoMessage() {}
+
+func (x *QueryEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryEventsResponse.ProtoReflect.Descriptor instead.
+func (*QueryEventsResponse) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *QueryEventsResponse) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// GetAnalyticsRequest mirrors the query parameters of GET /analytics. The
+// range is either window, such as 24h or 7d, ending at end or now, or start
+// and end; the filter's own timestamps are ignored.
+type GetAnalyticsRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Window           string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Start            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End              *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Filter           *EventFilter           `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	Granularity      string                 `protobuf:"bytes,5,opt,name=granularity,proto3" json:"granularity,omitempty"`
+	Tz               string                 `protobuf:"bytes,6,opt,name=tz,proto3" json:"tz,omitempty"`
+	GroupBy          string                 `protobuf:"bytes,7,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Top              int32                  `protobuf:"varint,8,opt,name=top,proto3" json:"top,omitempty"`
+	ExactUniqueUsers bool                   `protobuf:"varint,9,opt,name=exact_unique_users,json=exactUniqueUsers,proto3" json:"exact_unique_users,omitempty"`
+	IncludeSessions  bool                   `protobuf:"varint,10,opt,name=include_sessions,json=includeSessions,proto3" json:"include_sessions,omitempty"`
+	SessionGap       *durationpb.Duration   `protobuf:"bytes,11,opt,name=session_gap,json=sessionGap,proto3" json:"session_gap,omitempty"`
+	Stats            []string               `protobuf:"bytes,12,rep,name=stats,proto3" json:"stats,omitempty"`
+	HistogramBuckets int32                  `protobuf:"varint,13,opt,name=histogram_buckets,json=histogramBuckets,proto3" json:"histogram_buckets,omitempty"`
+	Compare          string                 `protobuf:"bytes,14,opt,name=compare,proto3" json:"compare,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetAnalyticsRequest) Reset() {
+	*x = GetAnalyticsRequest{}
+	mi := &file_events_v1_events_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAnalyticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAnalyticsRequest) ProtoMessage() {}
+
+func (x *GetAnalyticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAnalyticsRequest.ProtoReflect.Descriptor instead.
+func (*GetAnalyticsRequest) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetAnalyticsRequest) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *GetAnalyticsRequest) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *GetAnalyticsRequest) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *GetAnalyticsRequest) GetFilter() *EventFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *GetAnalyticsRequest) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+func (x *GetAnalyticsRequest) GetTz() string {
+	if x != nil {
+		return x.Tz
+	}
+	return ""
+}
+
+func (x *GetAnalyticsRequest) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *GetAnalyticsRequest) GetTop() int32 {
+	if x != nil {
+		return x.Top
+	}
+	return 0
+}
+
+func (x *GetAnalyticsRequest) GetExactUniqueUsers() bool {
+	if x != nil {
+		return x.ExactUniqueUsers
+	}
+	return false
+}
+
+func (x *GetAnalyticsRequest) GetIncludeSessions() bool {
+	if x != nil {
+		return x.IncludeSessions
+	}
+	return false
+}
+
+func (x *GetAnalyticsRequest) GetSessionGap() *durationpb.Duration {
+	if x != nil {
+		return x.SessionGap
+	}
+	return nil
+}
+
+func (x *GetAnalyticsRequest) GetStats() []string {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *GetAnalyticsRequest) GetHistogramBuckets() int32 {
+	if x != nil {
+		return x.HistogramBuckets
+	}
+	return 0
+}
+
+func (x *GetAnalyticsRequest) GetCompare() string {
+	if x != nil {
+		return x.Compare
+	}
+	return ""
+}
+
+type GetAnalyticsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Analytics     *Analytics             `protobuf:"bytes,1,opt,name=analytics,proto3" json:"analytics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAnalyticsResponse) Reset() {
+	*x = GetAnalyticsResponse{}
+	mi := &file_events_v1_events_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAnalyticsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAnalyticsResponse) ProtoMessage() {}
+
+func (x *GetAnalyticsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAnalyticsResponse.ProtoReflect.Descriptor instead.
+func (*GetAnalyticsResponse) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetAnalyticsResponse) GetAnalytics() *Analytics {
+	if x != nil {
+		return x.Analytics
+	}
+	return nil
+}
+
+type Analytics struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	TimeWindow             string                 `protobuf:"bytes,1,opt,name=time_window,json=timeWindow,proto3" json:"time_window,omitempty"`
+	Start                  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End                    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	TotalEvents            int64                  `protobuf:"varint,4,opt,name=total_events,json=totalEvents,proto3" json:"total_events,omitempty"`
+	EventsByType           map[string]int64       `protobuf:"bytes,5,rep,name=events_by_type,json=eventsByType,proto3" json:"events_by_type,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	UniqueUsers            int64                  `protobuf:"varint,6,opt,name=unique_users,json=uniqueUsers,proto3" json:"unique_users,omitempty"`
+	UniqueUsersApproximate bool                   `protobuf:"varint,7,opt,name=unique_users_approximate,json=uniqueUsersApproximate,proto3" json:"unique_users_approximate,omitempty"`
+	UniqueUsersError       float64                `protobuf:"fixed64,8,opt,name=unique_users_error,json=uniqueUsersError,proto3" json:"unique_users_error,omitempty"`
+	EventsPerHour          []*EventsPerHour       `protobuf:"bytes,9,rep,name=events_per_hour,json=eventsPerHour,proto3" json:"events_per_hour,omitempty"`
+	Granularity            string                 `protobuf:"bytes,10,opt,name=granularity,proto3" json:"granularity,omitempty"`
+	Series                 []*TimeBucket          `protobuf:"bytes,11,rep,name=series,proto3" json:"series,omitempty"`
+	GroupBy                string                 `protobuf:"bytes,12,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Groups                 []*AnalyticsGroup      `protobuf:"bytes,13,rep,name=groups,proto3" json:"groups,omitempty"`
+	Other                  *AnalyticsGroup        `protobuf:"bytes,14,opt,name=other,proto3" json:"other,omitempty"`
+	Revenue                *Revenue               `protobuf:"bytes,15,opt,name=revenue,proto3" json:"revenue,omitempty"`
+	Sessions               *SessionMetrics        `protobuf:"bytes,16,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	Stats                  []*PropertyStats       `protobuf:"bytes,17,rep,name=stats,proto3" json:"stats,omitempty"`
+	Comparison             *Comparison            `protobuf:"bytes,18,opt,name=comparison,proto3" json:"comparison,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Analytics) Reset() {
+	*x = Analytics{}
+	mi := &file_events_v1_events_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Analytics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Analytics) ProtoMessage() {}
+
+func (x *Analytics) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Analytics.ProtoReflect.Descriptor instead.
+func (*Analytics) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Analytics) GetTimeWindow() string {
+	if x != nil {
+		return x.TimeWindow
+	}
+	return ""
+}
+
+func (x *Analytics) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Analytics) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Analytics) GetTotalEvents() int64 {
+	if x != nil {
+		return x.TotalEvents
+	}
+	return 0
+}
+
+func (x *Analytics) GetEventsByType() map[string]int64 {
+	if x != nil {
+		return x.EventsByType
+	}
+	return nil
+}
+
+func (x *Analytics) GetUniqueUsers() int64 {
+	if x != nil {
+		return x.UniqueUsers
+	}
+	return 0
+}
+
+func (x *Analytics) GetUniqueUsersApproximate() bool {
+	if x != nil {
+		return x.UniqueUsersApproximate
+	}
+	return false
+}
+
+func (x *Analytics) GetUniqueUsersError() float64 {
+	if x != nil {
+		return x.UniqueUsersError
+	}
+	return 0
+}
+
+func (x *Analytics) GetEventsPerHour() []*EventsPerHour {
+	if x != nil {
+		return x.EventsPerHour
+	}
+	return nil
+}
+
+func (x *Analytics) GetGranularity() string {
+	if x != nil {
+		return x.Granularity
+	}
+	return ""
+}
+
+func (x *Analytics) GetSeries() []*TimeBucket {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+func (x *Analytics) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *Analytics) GetGroups() []*AnalyticsGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *Analytics) GetOther() *AnalyticsGroup {
+	if x != nil {
+		return x.Other
+	}
+	return nil
+}
+
+func (x *Analytics) GetRevenue() *Revenue {
+	if x != nil {
+		return x.Revenue
+	}
+	return nil
+}
+
+func (x *Analytics) GetSessions() *SessionMetrics {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *Analytics) GetStats() []*PropertyStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *Analytics) GetComparison() *Comparison {
+	if x != nil {
+		return x.Comparison
+	}
+	return nil
+}
+
+type EventsPerHour struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hour          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=hour,proto3" json:"hour,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventsPerHour) Reset() {
+	*x = EventsPerHour{}
+	mi := &file_events_v1_events_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventsPerHour) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventsPerHour) ProtoMessage() {}
+
+func (x *EventsPerHour) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventsPerHour.ProtoReflect.Descriptor instead.
+func (*EventsPerHour) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *EventsPerHour) GetHour() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Hour
+	}
+	return nil
+}
+
+func (x *EventsPerHour) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type TimeBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeBucket) Reset() {
+	*x = TimeBucket{}
+	mi := &file_events_v1_events_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeBucket) ProtoMessage() {}
+
+func (x *TimeBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeBucket.ProtoReflect.Descriptor instead.
+func (*TimeBucket) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *TimeBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *TimeBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AnalyticsGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	TotalEvents   int64                  `protobuf:"varint,2,opt,name=total_events,json=totalEvents,proto3" json:"total_events,omitempty"`
+	UniqueUsers   int64                  `protobuf:"varint,3,opt,name=unique_users,json=uniqueUsers,proto3" json:"unique_users,omitempty"`
+	Series        []*TimeBucket          `protobuf:"bytes,4,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyticsGroup) Reset() {
+	*x = AnalyticsGroup{}
+	mi := &file_events_v1_events_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyticsGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyticsGroup) ProtoMessage() {}
+
+func (x *AnalyticsGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyticsGroup.ProtoReflect.Descriptor instead.
+func (*AnalyticsGroup) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AnalyticsGroup) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *AnalyticsGroup) GetTotalEvents() int64 {
+	if x != nil {
+		return x.TotalEvents
+	}
+	return 0
+}
+
+func (x *AnalyticsGroup) GetUniqueUsers() int64 {
+	if x != nil {
+		return x.UniqueUsers
+	}
+	return 0
+}
+
+func (x *AnalyticsGroup) GetSeries() []*TimeBucket {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type Revenue struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Currency          string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	TotalRevenue      float64                `protobuf:"fixed64,2,opt,name=total_revenue,json=totalRevenue,proto3" json:"total_revenue,omitempty"`
+	Orders            int64                  `protobuf:"varint,3,opt,name=orders,proto3" json:"orders,omitempty"`
+	AverageOrderValue float64                `protobuf:"fixed64,4,opt,name=average_order_value,json=averageOrderValue,proto3" json:"average_order_value,omitempty"`
+	UnconvertedOrders int64                  `protobuf:"varint,5,opt,name=unconverted_orders,json=unconvertedOrders,proto3" json:"unconverted_orders,omitempty"`
+	RevenuePerHour    []*RevenuePerHour      `protobuf:"bytes,6,rep,name=revenue_per_hour,json=revenuePerHour,proto3" json:"revenue_per_hour,omitempty"`
+	TopProducts       []*ProductRevenue      `protobuf:"bytes,7,rep,name=top_products,json=topProducts,proto3" json:"top_products,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Revenue) Reset() {
+	*x = Revenue{}
+	mi := &file_events_v1_events_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Revenue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Revenue) ProtoMessage() {}
+
+func (x *Revenue) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Revenue.ProtoReflect.Descriptor instead.
+func (*Revenue) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Revenue) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Revenue) GetTotalRevenue() float64 {
+	if x != nil {
+		return x.TotalRevenue
+	}
+	return 0
+}
+
+func (x *Revenue) GetOrders() int64 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+func (x *Revenue) GetAverageOrderValue() float64 {
+	if x != nil {
+		return x.AverageOrderValue
+	}
+	return 0
+}
+
+func (x *Revenue) GetUnconvertedOrders() int64 {
+	if x != nil {
+		return x.UnconvertedOrders
+	}
+	return 0
+}
+
+func (x *Revenue) GetRevenuePerHour() []*RevenuePerHour {
+	if x != nil {
+		return x.RevenuePerHour
+	}
+	return nil
+}
+
+func (x *Revenue) GetTopProducts() []*ProductRevenue {
+	if x != nil {
+		return x.TopProducts
+	}
+	return nil
+}
+
+type RevenuePerHour struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hour          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=hour,proto3" json:"hour,omitempty"`
+	Revenue       float64                `protobuf:"fixed64,2,opt,name=revenue,proto3" json:"revenue,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevenuePerHour) Reset() {
+	*x = RevenuePerHour{}
+	mi := &file_events_v1_events_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevenuePerHour) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevenuePerHour) ProtoMessage() {}
+
+func (x *RevenuePerHour) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevenuePerHour.ProtoReflect.Descriptor instead.
+func (*RevenuePerHour) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RevenuePerHour) GetHour() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Hour
+	}
+	return nil
+}
+
+func (x *RevenuePerHour) GetRevenue() float64 {
+	if x != nil {
+		return x.Revenue
+	}
+	return 0
+}
+
+type ProductRevenue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Revenue       float64                `protobuf:"fixed64,2,opt,name=revenue,proto3" json:"revenue,omitempty"`
+	Orders        int64                  `protobuf:"varint,3,opt,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductRevenue) Reset() {
+	*x = ProductRevenue{}
+	mi := &file_events_v1_events_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductRevenue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductRevenue) ProtoMessage() {}
+
+func (x *ProductRevenue) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductRevenue.ProtoReflect.Descriptor instead.
+func (*ProductRevenue) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ProductRevenue) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ProductRevenue) GetRevenue() float64 {
+	if x != nil {
+		return x.Revenue
+	}
+	return 0
+}
+
+func (x *ProductRevenue) GetOrders() int64 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+type SessionMetrics struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	SessionGap            string                 `protobuf:"bytes,1,opt,name=session_gap,json=sessionGap,proto3" json:"session_gap,omitempty"`
+	Sessions              int64                  `protobuf:"varint,2,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	MeanDurationSeconds   float64                `protobuf:"fixed64,3,opt,name=mean_duration_seconds,json=meanDurationSeconds,proto3" json:"mean_duration_seconds,omitempty"`
+	MedianDurationSeconds float64                `protobuf:"fixed64,4,opt,name=median_duration_seconds,json=medianDurationSeconds,proto3" json:"median_duration_seconds,omitempty"`
+	BounceRate            float64                `protobuf:"fixed64,5,opt,name=bounce_rate,json=bounceRate,proto3" json:"bounce_rate,omitempty"`
+	EventsPerSession      float64                `protobuf:"fixed64,6,opt,name=events_per_session,json=eventsPerSession,proto3" json:"events_per_session,omitempty"`
+	PagesPerSession       float64                `protobuf:"fixed64,7,opt,name=pages_per_session,json=pagesPerSession,proto3" json:"pages_per_session,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *SessionMetrics) Reset() {
+	*x = SessionMetrics{}
+	mi := &file_events_v1_events_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionMetrics) ProtoMessage() {}
+
+func (x *SessionMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionMetrics.ProtoReflect.Descriptor instead.
+func (*SessionMetrics) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SessionMetrics) GetSessionGap() string {
+	if x != nil {
+		return x.SessionGap
+	}
+	return ""
+}
+
+func (x *SessionMetrics) GetSessions() int64 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *SessionMetrics) GetMeanDurationSeconds() float64 {
+	if x != nil {
+		return x.MeanDurationSeconds
+	}
+	return 0
+}
+
+func (x *SessionMetrics) GetMedianDurationSeconds() float64 {
+	if x != nil {
+		return x.MedianDurationSeconds
+	}
+	return 0
+}
+
+func (x *SessionMetrics) GetBounceRate() float64 {
+	if x != nil {
+		return x.BounceRate
+	}
+	return 0
+}
+
+func (x *SessionMetrics) GetEventsPerSession() float64 {
+	if x != nil {
+		return x.EventsPerSession
+	}
+	return 0
+}
+
+func (x *SessionMetrics) GetPagesPerSession() float64 {
+	if x != nil {
+		return x.PagesPerSession
+	}
+	return 0
+}
+
+type PropertyStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Property        string                 `protobuf:"bytes,1,opt,name=property,proto3" json:"property,omitempty"`
+	Count           int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Min             float64                `protobuf:"fixed64,3,opt,name=min,proto3" json:"min,omitempty"`
+	Max             float64                `protobuf:"fixed64,4,opt,name=max,proto3" json:"max,omitempty"`
+	Mean            float64                `protobuf:"fixed64,5,opt,name=mean,proto3" json:"mean,omitempty"`
+	Stddev          float64                `protobuf:"fixed64,6,opt,name=stddev,proto3" json:"stddev,omitempty"`
+	P50             float64                `protobuf:"fixed64,7,opt,name=p50,proto3" json:"p50,omitempty"`
+	P90             float64                `protobuf:"fixed64,8,opt,name=p90,proto3" json:"p90,omitempty"`
+	P95             float64                `protobuf:"fixed64,9,opt,name=p95,proto3" json:"p95,omitempty"`
+	P99             float64                `protobuf:"fixed64,10,opt,name=p99,proto3" json:"p99,omitempty"`
+	Approximate     bool                   `protobuf:"varint,11,opt,name=approximate,proto3" json:"approximate,omitempty"`
+	PercentileError float64                `protobuf:"fixed64,12,opt,name=percentile_error,json=percentileError,proto3" json:"percentile_error,omitempty"`
+	Histogram       []*HistogramBucket     `protobuf:"bytes,13,rep,name=histogram,proto3" json:"histogram,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PropertyStats) Reset() {
+	*x = PropertyStats{}
+	mi := &file_events_v1_events_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyStats) ProtoMessage() {}
+
+func (x *PropertyStats) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyStats.ProtoReflect.Descriptor instead.
+func (*PropertyStats) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *PropertyStats) GetProperty() string {
+	if x != nil {
+		return x.Property
+	}
+	return ""
+}
+
+func (x *PropertyStats) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetStddev() float64 {
+	if x != nil {
+		return x.Stddev
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetP50() float64 {
+	if x != nil {
+		return x.P50
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetP90() float64 {
+	if x != nil {
+		return x.P90
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetP95() float64 {
+	if x != nil {
+		return x.P95
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetP99() float64 {
+	if x != nil {
+		return x.P99
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetApproximate() bool {
+	if x != nil {
+		return x.Approximate
+	}
+	return false
+}
+
+func (x *PropertyStats) GetPercentileError() float64 {
+	if x != nil {
+		return x.PercentileError
+	}
+	return 0
+}
+
+func (x *PropertyStats) GetHistogram() []*HistogramBucket {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+type HistogramBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         float64                `protobuf:"fixed64,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           float64                `protobuf:"fixed64,2,opt,name=end,proto3" json:"end,omitempty"`
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistogramBucket) Reset() {
+	*x = HistogramBucket{}
+	mi := &file_events_v1_events_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistogramBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistogramBucket) ProtoMessage() {}
+
+func (x *HistogramBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistogramBucket.ProtoReflect.Descriptor instead.
+func (*HistogramBucket) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *HistogramBucket) GetStart() float64 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *HistogramBucket) GetEnd() float64 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *HistogramBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type Comparison struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Compare       string                  `protobuf:"bytes,1,opt,name=compare,proto3" json:"compare,omitempty"`
+	Start         *timestamppb.Timestamp  `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp  `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	TotalEvents   *MetricDelta            `protobuf:"bytes,4,opt,name=total_events,json=totalEvents,proto3" json:"total_events,omitempty"`
+	EventsByType  map[string]*MetricDelta `protobuf:"bytes,5,rep,name=events_by_type,json=eventsByType,proto3" json:"events_by_type,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	UniqueUsers   *MetricDelta            `protobuf:"bytes,6,opt,name=unique_users,json=uniqueUsers,proto3" json:"unique_users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Comparison) Reset() {
+	*x = Comparison{}
+	mi := &file_events_v1_events_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Comparison) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comparison) ProtoMessage() {}
+
+func (x *Comparison) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comparison.ProtoReflect.Descriptor instead.
+func (*Comparison) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Comparison) GetCompare() string {
+	if x != nil {
+		return x.Compare
+	}
+	return ""
+}
+
+func (x *Comparison) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Comparison) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *Comparison) GetTotalEvents() *MetricDelta {
+	if x != nil {
+		return x.TotalEvents
+	}
+	return nil
+}
+
+func (x *Comparison) GetEventsByType() map[string]*MetricDelta {
+	if x != nil {
+		return x.EventsByType
+	}
+	return nil
+}
+
+func (x *Comparison) GetUniqueUsers() *MetricDelta {
+	if x != nil {
+		return x.UniqueUsers
+	}
+	return nil
+}
+
+type MetricDelta struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Previous      int64                  `protobuf:"varint,1,opt,name=previous,proto3" json:"previous,omitempty"`
+	Delta         int64                  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	PercentChange *float64               `protobuf:"fixed64,3,opt,name=percent_change,json=percentChange,proto3,oneof" json:"percent_change,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricDelta) Reset() {
+	*x = MetricDelta{}
+	mi := &file_events_v1_events_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricDelta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricDelta) ProtoMessage() {}
+
+func (x *MetricDelta) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricDelta.ProtoReflect.Descriptor instead.
+func (*MetricDelta) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MetricDelta) GetPrevious() int64 {
+	if x != nil {
+		return x.Previous
+	}
+	return 0
+}
+
+func (x *MetricDelta) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *MetricDelta) GetPercentChange() float64 {
+	if x != nil && x.PercentChange != nil {
+		return *x.PercentChange
+	}
+	return 0
+}
+
+type StreamEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_events_v1_events_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *StreamEventsRequest) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type StreamEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*StreamEventsResponse_Event
+	//	*StreamEventsResponse_Rejected
+	Result        isStreamEventsResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsResponse) Reset() {
+	*x = StreamEventsResponse{}
+	mi := &file_events_v1_events_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsResponse) ProtoMessage() {}
+
+func (x *StreamEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsResponse.ProtoReflect.Descriptor instead.
+func (*StreamEventsResponse) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *StreamEventsResponse) GetResult() isStreamEventsResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *StreamEventsResponse) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Result.(*StreamEventsResponse_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *StreamEventsResponse) GetRejected() *RejectedEvent {
+	if x != nil {
+		if x, ok := x.Result.(*StreamEventsResponse_Rejected); ok {
+			return x.Rejected
+		}
+	}
+	return nil
+}
+
+type isStreamEventsResponse_Result interface {
+	isStreamEventsResponse_Result()
+}
+
+type StreamEventsResponse_Event struct {
+	// event was stored by one of the open streams.
+	Event *Event `protobuf:"bytes,1,opt,name=event,proto3,oneof"`
+}
+
+type StreamEventsResponse_Rejected struct {
+	// rejected is an event this stream sent that was not stored.
+	Rejected *RejectedEvent `protobuf:"bytes,2,opt,name=rejected,proto3,oneof"`
+}
+
+func (*StreamEventsResponse_Event) isStreamEventsResponse_Result() {}
+
+func (*StreamEventsResponse_Rejected) isStreamEventsResponse_Result() {}
+
+type RejectedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectedEvent) Reset() {
+	*x = RejectedEvent{}
+	mi := &file_events_v1_events_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectedEvent) ProtoMessage() {}
+
+func (x *RejectedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_events_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectedEvent.ProtoReflect.Descriptor instead.
+func (*RejectedEvent) Descriptor() ([]byte, []int) {
+	return file_events_v1_events_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RejectedEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *RejectedEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_events_v1_events_proto protoreflect.FileDescriptor
+
+const file_events_v1_events_proto_rawDesc = "" +
+	"\n" +
+	"\x16events/v1/events.proto\x12\tevents.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe6\x01\n" +
+	"\x05Event\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x123\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\x0e2\x14.events.v1.EventTypeR\teventType\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12:\n" +
+	"\n" +
+	"properties\x18\x05 \x01(\v2\x1a.events.v1.EventPropertiesR\n" +
+	"properties\"\xd0\x01\n" +
+	"\x0fEventProperties\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\tR\x04page\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x12\n" +
+	"\x04link\x18\x05 \x01(\tR\x04link\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04name\x18\b \x01(\tR\x04name\"\xb0\x02\n" +
+	"\vEventFilter\x12\x1c\n" +
+	"\auser_id\x18\x01 \x01(\tH\x00R\x06userId\x88\x01\x01\x123\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\x0e2\x14.events.v1.EventTypeR\teventType\x12C\n" +
+	"\x0fstart_timestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x0estartTimestamp\x12?\n" +
+	"\rend_timestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fendTimestamp\x12<\n" +
+	"\n" +
+	"properties\x18\x05 \x03(\v2\x1c.events.v1.PropertyPredicateR\n" +
+	"propertiesB\n" +
+	"\n" +
+	"\b_user_id\"O\n" +
+	"\x11PropertyPredicate\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x0e\n" +
+	"\x02op\x18\x02 \x01(\tR\x02op\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"?\n" +
+	"\x13CreateEventsRequest\x12(\n" +
+	"\x06events\x18\x01 \x03(\v2\x10.events.v1.EventR\x06events\"N\n" +
+	"\x14CreateEventsResponse\x126\n" +
+	"\aresults\x18\x01 \x03(\v2\x1c.events.v1.CreateEventResultR\aresults\"k\n" +
+	"\x11CreateEventResult\x12&\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.events.v1.EventR\x05event\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\",\n" +
+	"\x0fGetEventRequest\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\":\n" +
+	"\x10GetEventResponse\x12&\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.events.v1.EventR\x05event\"D\n" +
+	"\x12QueryEventsRequest\x12.\n" +
+	"\x06filter\x18\x01 \x01(\v2\x16.events.v1.EventFilterR\x06filter\"=\n" +
+	"\x13QueryEventsResponse\x12&\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.events.v1.EventR\x05event\"\x8e\x04\n" +
+	"\x13GetAnalyticsRequest\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12.\n" +
+	"\x06filter\x18\x04 \x01(\v2\x16.events.v1.EventFilterR\x06filter\x12 \n" +
+	"\vgranularity\x18\x05 \x01(\tR\vgranularity\x12\x0e\n" +
+	"\x02tz\x18\x06 \x01(\tR\x02tz\x12\x19\n" +
+	"\bgroup_by\x18\a \x01(\tR\agroupBy\x12\x10\n" +
+	"\x03top\x18\b \x01(\x05R\x03top\x12,\n" +
+	"\x12exact_unique_users\x18\t \x01(\bR\x10exactUniqueUsers\x12)\n" +
+	"\x10include_sessions\x18\n" +
+	" \x01(\bR\x0fincludeSessions\x12:\n" +
+	"\vsession_gap\x18\v \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"sessionGap\x12\x14\n" +
+	"\x05stats\x18\f \x03(\tR\x05stats\x12+\n" +
+	"\x11histogram_buckets\x18\r \x01(\x05R\x10histogramBuckets\x12\x18\n" +
+	"\acompare\x18\x0e \x01(\tR\acompare\"J\n" +
+	"\x14GetAnalyticsResponse\x122\n" +
+	"\tanalytics\x18\x01 \x01(\v2\x14.events.v1.AnalyticsR\tanalytics\"\xa7\a\n" +
+	"\tAnalytics\x12\x1f\n" +
+	"\vtime_window\x18\x01 \x01(\tR\n" +
+	"timeWindow\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12!\n" +
+	"\ftotal_events\x18\x04 \x01(\x03R\vtotalEvents\x12L\n" +
+	"\x0eevents_by_type\x18\x05 \x03(\v2&.events.v1.Analytics.EventsByTypeEntryR\feventsByType\x12!\n" +
+	"\funique_users\x18\x06 \x01(\x03R\vuniqueUsers\x128\n" +
+	"\x18unique_users_approximate\x18\a \x01(\bR\x16uniqueUsersApproximate\x12,\n" +
+	"\x12unique_users_error\x18\b \x01(\x01R\x10uniqueUsersError\x12@\n" +
+	"\x0fevents_per_hour\x18\t \x03(\v2\x18.events.v1.EventsPerHourR\reventsPerHour\x12 \n" +
+	"\vgranularity\x18\n" +
+	" \x01(\tR\vgranularity\x12-\n" +
+	"\x06series\x18\v \x03(\v2\x15.events.v1.TimeBucketR\x06series\x12\x19\n" +
+	"\bgroup_by\x18\f \x01(\tR\agroupBy\x121\n" +
+	"\x06groups\x18\r \x03(\v2\x19.events.v1.AnalyticsGroupR\x06groups\x12/\n" +
+	"\x05other\x18\x0e \x01(\v2\x19.events.v1.AnalyticsGroupR\x05other\x12,\n" +
+	"\arevenue\x18\x0f \x01(\v2\x12.events.v1.RevenueR\arevenue\x125\n" +
+	"\bsessions\x18\x10 \x01(\v2\x19.events.v1.SessionMetricsR\bsessions\x12.\n" +
+	"\x05stats\x18\x11 \x03(\v2\x18.events.v1.PropertyStatsR\x05stats\x125\n" +
+	"\n" +
+	"comparison\x18\x12 \x01(\v2\x15.events.v1.ComparisonR\n" +
+	"comparison\x1a?\n" +
+	"\x11EventsByTypeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01\"U\n" +
+	"\rEventsPerHour\x12.\n" +
+	"\x04hour\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04hour\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"T\n" +
+	"\n" +
+	"TimeBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\x97\x01\n" +
+	"\x0eAnalyticsGroup\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12!\n" +
+	"\ftotal_events\x18\x02 \x01(\x03R\vtotalEvents\x12!\n" +
+	"\funique_users\x18\x03 \x01(\x03R\vuniqueUsers\x12-\n" +
+	"\x06series\x18\x04 \x03(\v2\x15.events.v1.TimeBucketR\x06series\"\xc4\x02\n" +
+	"\aRevenue\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12#\n" +
+	"\rtotal_revenue\x18\x02 \x01(\x01R\ftotalRevenue\x12\x16\n" +
+	"\x06orders\x18\x03 \x01(\x03R\x06orders\x12.\n" +
+	"\x13average_order_value\x18\x04 \x01(\x01R\x11averageOrderValue\x12-\n" +
+	"\x12unconverted_orders\x18\x05 \x01(\x03R\x11unconvertedOrders\x12C\n" +
+	"\x10revenue_per_hour\x18\x06 \x03(\v2\x19.events.v1.RevenuePerHourR\x0erevenuePerHour\x12<\n" +
+	"\ftop_products\x18\a \x03(\v2\x19.events.v1.ProductRevenueR\vtopProducts\"Z\n" +
+	"\x0eRevenuePerHour\x12.\n" +
+	"\x04hour\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04hour\x12\x18\n" +
+	"\arevenue\x18\x02 \x01(\x01R\arevenue\"a\n" +
+	"\x0eProductRevenue\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x18\n" +
+	"\arevenue\x18\x02 \x01(\x01R\arevenue\x12\x16\n" +
+	"\x06orders\x18\x03 \x01(\x03R\x06orders\"\xb4\x02\n" +
+	"\x0eSessionMetrics\x12\x1f\n" +
+	"\vsession_gap\x18\x01 \x01(\tR\n" +
+	"sessionGap\x12\x1a\n" +
+	"\bsessions\x18\x02 \x01(\x03R\bsessions\x122\n" +
+	"\x15mean_duration_seconds\x18\x03 \x01(\x01R\x13meanDurationSeconds\x126\n" +
+	"\x17median_duration_seconds\x18\x04 \x01(\x01R\x15medianDurationSeconds\x12\x1f\n" +
+	"\vbounce_rate\x18\x05 \x01(\x01R\n" +
+	"bounceRate\x12,\n" +
+	"\x12events_per_session\x18\x06 \x01(\x01R\x10eventsPerSession\x12*\n" +
+	"\x11pages_per_session\x18\a \x01(\x01R\x0fpagesPerSession\"\xe0\x02\n" +
+	"\rPropertyStats\x12\x1a\n" +
+	"\bproperty\x18\x01 \x01(\tR\bproperty\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x10\n" +
+	"\x03min\x18\x03 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x04 \x01(\x01R\x03max\x12\x12\n" +
+	"\x04mean\x18\x05 \x01(\x01R\x04mean\x12\x16\n" +
+	"\x06stddev\x18\x06 \x01(\x01R\x06stddev\x12\x10\n" +
+	"\x03p50\x18\a \x01(\x01R\x03p50\x12\x10\n" +
+	"\x03p90\x18\b \x01(\x01R\x03p90\x12\x10\n" +
+	"\x03p95\x18\t \x01(\x01R\x03p95\x12\x10\n" +
+	"\x03p99\x18\n" +
+	" \x01(\x01R\x03p99\x12 \n" +
+	"\vapproximate\x18\v \x01(\bR\vapproximate\x12)\n" +
+	"\x10percentile_error\x18\f \x01(\x01R\x0fpercentileError\x128\n" +
+	"\thistogram\x18\r \x03(\v2\x1a.events.v1.HistogramBucketR\thistogram\"O\n" +
+	"\x0fHistogramBucket\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x01R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x01R\x03end\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"\xa4\x03\n" +
+	"\n" +
+	"Comparison\x12\x18\n" +
+	"\acompare\x18\x01 \x01(\tR\acompare\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x129\n" +
+	"\ftotal_events\x18\x04 \x01(\v2\x16.events.v1.MetricDeltaR\vtotalEvents\x12M\n" +
+	"\x0eevents_by_type\x18\x05 \x03(\v2'.events.v1.Comparison.EventsByTypeEntryR\feventsByType\x129\n" +
+	"\funique_users\x18\x06 \x01(\v2\x16.events.v1.MetricDeltaR\vuniqueUsers\x1aW\n" +
+	"\x11EventsByTypeEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.events.v1.MetricDeltaR\x05value:\x028\x01\"~\n" +
+	"\vMetricDelta\x12\x1a\n" +
+	"\bprevious\x18\x01 \x01(\x03R\bprevious\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x03R\x05delta\x12*\n" +
+	"\x0epercent_change\x18\x03 \x01(\x01H\x00R\rpercentChange\x88\x01\x01B\x11\n" +
+	"\x0f_percent_change\"=\n" +
+	"\x13StreamEventsRequest\x12&\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.events.v1.EventR\x05event\"\x82\x01\n" +
+	"\x14StreamEventsResponse\x12(\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.events.v1.EventH\x00R\x05event\x126\n" +
+	"\brejected\x18\x02 \x01(\v2\x18.events.v1.RejectedEventH\x00R\brejectedB\b\n" +
+	"\x06result\"@\n" +
+	"\rRejectedEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error*\x9e\x01\n" +
+	"\tEventType\x12\x1a\n" +
+	"\x16EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14EVENT_TYPE_PAGE_VIEW\x10\x01\x12\x14\n" +
+	"\x10EVENT_TYPE_CLICK\x10\x02\x12\x17\n" +
+	"\x13EVENT_TYPE_PURCHASE\x10\x03\x12\x15\n" +
+	"\x11EVENT_TYPE_SIGNUP\x10\x04\x12\x15\n" +
+	"\x11EVENT_TYPE_CUSTOM\x10\x052\x9a\x03\n" +
+	"\fEventService\x12O\n" +
+	"\fCreateEvents\x12\x1e.events.v1.CreateEventsRequest\x1a\x1f.events.v1.CreateEventsResponse\x12C\n" +
+	"\bGetEvent\x12\x1a.events.v1.GetEventRequest\x1a\x1b.events.v1.GetEventResponse\x12N\n" +
+	"\vQueryEvents\x12\x1d.events.v1.QueryEventsRequest\x1a\x1e.events.v1.QueryEventsResponse0\x01\x12O\n" +
+	"\fGetAnalytics\x12\x1e.events.v1.GetAnalyticsRequest\x1a\x1f.events.v1.GetAnalyticsResponse\x12S\n" +
+	"\fStreamEvents\x12\x1e.events.v1.StreamEventsRequest\x1a\x1f.events.v1.StreamEventsResponse(\x010\x01BEZCgithub.com/dnakolan/event-processing-service/api/events/v1;eventsv1b\x06proto3"
+
+var (
+	file_events_v1_events_proto_rawDescOnce sync.Once
+	file_events_v1_events_proto_rawDescData []byte
+)
+
+func file_events_v1_events_proto_rawDescGZIP() []byte {
+	file_events_v1_events_proto_rawDescOnce.Do(func() {
+		file_events_v1_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_v1_events_proto_rawDesc), len(file_events_v1_events_proto_rawDesc)))
+	})
+	return file_events_v1_events_proto_rawDescData
+}
+
+var file_events_v1_events_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_events_v1_events_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
+var file_events_v1_events_proto_goTypes = []any{
+	(EventType)(0),                // 0: events.v1.EventType
+	(*Event)(nil),                 // 1: events.v1.Event
+	(*EventProperties)(nil),       // 2: events.v1.EventProperties
+	(*EventFilter)(nil),           // 3: events.v1.EventFilter
+	(*PropertyPredicate)(nil),     // 4: events.v1.PropertyPredicate
+	(*CreateEventsRequest)(nil),   // 5: events.v1.CreateEventsRequest
+	(*CreateEventsResponse)(nil),  // 6: events.v1.CreateEventsResponse
+	(*CreateEventResult)(nil),     // 7: events.v1.CreateEventResult
+	(*GetEventRequest)(nil),       // 8: events.v1.GetEventRequest
+	(*GetEventResponse)(nil),      // 9: events.v1.GetEventResponse
+	(*QueryEventsRequest)(nil),    // 10: events.v1.QueryEventsRequest
+	(*QueryEventsResponse)(nil),   // 11: events.v1.QueryEventsResponse
+	(*GetAnalyticsRequest)(nil),   // 12: events.v1.GetAnalyticsRequest
+	(*GetAnalyticsResponse)(nil),  // 13: events.v1.GetAnalyticsResponse
+	(*Analytics)(nil),             // 14: events.v1.Analytics
+	(*EventsPerHour)(nil),         // 15: events.v1.EventsPerHour
+	(*TimeBucket)(nil),            // 16: events.v1.TimeBucket
+	(*AnalyticsGroup)(nil),        // 17: events.v1.AnalyticsGroup
+	(*Revenue)(nil),               // 18: events.v1.Revenue
+	(*RevenuePerHour)(nil),        // 19: events.v1.RevenuePerHour
+	(*ProductRevenue)(nil),        // 20: events.v1.ProductRevenue
+	(*SessionMetrics)(nil),        // 21: events.v1.SessionMetrics
+	(*PropertyStats)(nil),         // 22: events.v1.PropertyStats
+	(*HistogramBucket)(nil),       // 23: events.v1.HistogramBucket
+	(*Comparison)(nil),            // 24: events.v1.Comparison
+	(*MetricDelta)(nil),           // 25: events.v1.MetricDelta
+	(*StreamEventsRequest)(nil),   // 26: events.v1.StreamEventsRequest
+	(*StreamEventsResponse)(nil),  // 27: events.v1.StreamEventsResponse
+	(*RejectedEvent)(nil),         // 28: events.v1.RejectedEvent
+	nil,                           // 29: events.v1.Analytics.EventsByTypeEntry
+	nil,                           // 30: events.v1.Comparison.EventsByTypeEntry
+	(*timestamppb.Timestamp)(nil), // 31: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 32: google.protobuf.Duration
+}
+var file_events_v1_events_proto_depIdxs = []int32{
+	0,  // 0: events.v1.Event.event_type:type_name -> events.v1.EventType
+	31, // 1: events.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 2: events.v1.Event.properties:type_name -> events.v1.EventProperties
+	0,  // 3: events.v1.EventFilter.event_type:type_name -> events.v1.EventType
+	31, // 4: events.v1.EventFilter.start_timestamp:type_name -> google.protobuf.Timestamp
+	31, // 5: events.v1.EventFilter.end_timestamp:type_name -> google.protobuf.Timestamp
+	4,  // 6: events.v1.EventFilter.properties:type_name -> events.v1.PropertyPredicate
+	1,  // 7: events.v1.CreateEventsRequest.events:type_name -> events.v1.Event
+	7,  // 8: events.v1.CreateEventsResponse.results:type_name -> events.v1.CreateEventResult
+	1,  // 9: events.v1.CreateEventResult.event:type_name -> events.v1.Event
+	1,  // 10: events.v1.GetEventResponse.event:type_name -> events.v1.Event
+	3,  // 11: events.v1.QueryEventsRequest.filter:type_name -> events.v1.EventFilter
+	1,  // 12: events.v1.QueryEventsResponse.event:type_name -> events.v1.Event
+	31, // 13: events.v1.GetAnalyticsRequest.start:type_name -> google.protobuf.Timestamp
+	31, // 14: events.v1.GetAnalyticsRequest.end:type_name -> google.protobuf.Timestamp
+	3,  // 15: events.v1.GetAnalyticsRequest.filter:type_name -> events.v1.EventFilter
+	32, // 16: events.v1.GetAnalyticsRequest.session_gap:type_name -> google.protobuf.Duration
+	14, // 17: events.v1.GetAnalyticsResponse.analytics:type_name -> events.v1.Analytics
+	31, // 18: events.v1.Analytics.start:type_name -> google.protobuf.Timestamp
+	31, // 19: events.v1.Analytics.end:type_name -> google.protobuf.Timestamp
+	29, // 20: events.v1.Analytics.events_by_type:type_name -> events.v1.Analytics.EventsByTypeEntry
+	15, // 21: events.v1.Analytics.events_per_hour:type_name -> events.v1.EventsPerHour
+	16, // 22: events.v1.Analytics.series:type_name -> events.v1.TimeBucket
+	17, // 23: events.v1.Analytics.groups:type_name -> events.v1.AnalyticsGroup
+	17, // 24: events.v1.Analytics.other:type_name -> events.v1.AnalyticsGroup
+	18, // 25: events.v1.Analytics.revenue:type_name -> events.v1.Revenue
+	21, // 26: events.v1.Analytics.sessions:type_name -> events.v1.SessionMetrics
+	22, // 27: events.v1.Analytics.stats:type_name -> events.v1.PropertyStats
+	24, // 28: events.v1.Analytics.comparison:type_name -> events.v1.Comparison
+	31, // 29: events.v1.EventsPerHour.hour:type_name -> google.protobuf.Timestamp
+	31, // 30: events.v1.TimeBucket.start:type_name -> google.protobuf.Timestamp
+	16, // 31: events.v1.AnalyticsGroup.series:type_name -> events.v1.TimeBucket
+	19, // 32: events.v1.Revenue.revenue_per_hour:type_name -> events.v1.RevenuePerHour
+	20, // 33: events.v1.Revenue.top_products:type_name -> events.v1.ProductRevenue
+	31, // 34: events.v1.RevenuePerHour.hour:type_name -> google.protobuf.Timestamp
+	23, // 35: events.v1.PropertyStats.histogram:type_name -> events.v1.HistogramBucket
+	31, // 36: events.v1.Comparison.start:type_name -> google.protobuf.Timestamp
+	31, // 37: events.v1.Comparison.end:type_name -> google.protobuf.Timestamp
+	25, // 38: events.v1.Comparison.total_events:type_name -> events.v1.MetricDelta
+	30, // 39: events.v1.Comparison.events_by_type:type_name -> events.v1.Comparison.EventsByTypeEntry
+	25, // 40: events.v1.Comparison.unique_users:type_name -> events.v1.MetricDelta
+	1,  // 41: events.v1.StreamEventsRequest.event:type_name -> events.v1.Event
+	1,  // 42: events.v1.StreamEventsResponse.event:type_name -> events.v1.Event
+	28, // 43: events.v1.StreamEventsResponse.rejected:type_name -> events.v1.RejectedEvent
+	25, // 44: events.v1.Comparison.EventsByTypeEntry.value:type_name -> events.v1.MetricDelta
+	5,  // 45: events.v1.EventService.CreateEvents:input_type -> events.v1.CreateEventsRequest
+	8,  // 46: events.v1.EventService.GetEvent:input_type -> events.v1.GetEventRequest
+	10, // 47: events.v1.EventService.QueryEvents:input_type -> events.v1.QueryEventsRequest
+	12, // 48: events.v1.EventService.GetAnalytics:input_type -> events.v1.GetAnalyticsRequest
+	26, // 49: events.v1.EventService.StreamEvents:input_type -> events.v1.StreamEventsRequest
+	6,  // 50: events.v1.EventService.CreateEvents:output_type -> events.v1.CreateEventsResponse
+	9,  // 51: events.v1.EventService.GetEvent:output_type -> events.v1.GetEventResponse
+	11, // 52: events.v1.EventService.QueryEvents:output_type -> events.v1.QueryEventsResponse
+	13, // 53: events.v1.EventService.GetAnalytics:output_type -> events.v1.GetAnalyticsResponse
+	27, // 54: events.v1.EventService.StreamEvents:output_type -> events.v1.StreamEventsResponse
+	50, // [50:55] is the sub-list for method output_type
+	45, // [45:50] is the sub-list for method input_type
+	45, // [45:45] is the sub-list for extension type_name
+	45, // [45:45] is the sub-list for extension extendee
+	0,  // [0:45] is the sub-list for field type_name
+}
+
+func init() { file_events_v1_events_proto_init() }
+func file_events_v1_events_proto_init() {
+	if File_events_v1_events_proto != nil {
+		return
+	}
+	file_events_v1_events_proto_msgTypes[2].OneofWrappers = []any{}
+	file_events_v1_events_proto_msgTypes[24].OneofWrappers = []any{}
+	file_events_v1_events_proto_msgTypes[26].OneofWrappers = []any{
+		(*StreamEventsResponse_Event)(nil),
+		(*StreamEventsResponse_Rejected)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_v1_events_proto_rawDesc), len(file_events_v1_events_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   30,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_events_v1_events_proto_goTypes,
+		DependencyIndexes: file_events_v1_events_proto_depIdxs,
+		EnumInfos:         file_events_v1_events_proto_enumTypes,
+		MessageInfos:      file_events_v1_events_proto_msgTypes,
+	}.Build()
+	File_events_v1_events_proto = out.File
+	file_events_v1_events_proto_goTypes = nil
+	file_events_v1_events_proto_depIdxs = nil
+}
@@ -5,17 +5,25 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
+	eventsv1 "github.com/dnakolan/event-processing-service/api/events/v1"
 	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/grpcapi"
 	"github.com/dnakolan/event-processing-service/internal/handlers"
+	"github.com/dnakolan/event-processing-service/internal/notifiers"
 	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/sinks"
+	"github.com/dnakolan/event-processing-service/internal/sources"
 	"github.com/dnakolan/event-processing-service/internal/storage"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -29,19 +37,88 @@ func main() {
 
 	storage := storage.NewEventStorage()
 
-	eventsService := services.NewEventsService(storage)
-	analyticsService := services.NewAnalyticsService(storage)
+	sinkManager, err := sinks.NewManager(cfg.Sinks)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	eventStream := services.NewEventStream()
+	eventsService := services.NewEventsService(storage, sinkManager, eventStream)
+	analyticsService := services.NewAnalyticsService(storage, cfg.Analytics)
+	usersService := services.NewUsersService(storage, cfg.Analytics)
+	importsService := services.NewImportsService(eventsService)
+	exportsService := services.NewExportsService(storage, cfg.Exports)
+	segmentService := services.NewSegmentService(eventsService, usersService, cfg.Segment)
+
+	alertStream := notifiers.NewStreamNotifier()
+	alertNotifiers := []notifiers.Notifier{notifiers.NewLogNotifier(), alertStream}
+	if cfg.Alerts.WebhookURL != "" {
+		alertNotifiers = append(alertNotifiers, notifiers.NewWebhookNotifier(cfg.Alerts.WebhookURL))
+	}
+	alertsService := services.NewAlertsService(analyticsService, cfg.Alerts, alertNotifiers...)
+	webhooksService := services.NewWebhooksService(storage, cfg.Webhooks)
+
+	sourceManager, err := sources.NewManager(cfg.Sources, eventsService)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
 
 	healthHandler := handlers.NewHealthHandler()
-	eventsHandler := handlers.NewEventsHandler(eventsService)
+	eventsHandler := handlers.NewEventsHandler(eventsService, eventStream)
+	importsHandler := handlers.NewImportsHandler(importsService)
+	exportsHandler := handlers.NewExportsHandler(exportsService)
+	segmentHandler := handlers.NewSegmentHandler(segmentService)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	usersHandler := handlers.NewUsersHandler(usersService)
+	alertsHandler := handlers.NewAlertsHandler(alertsService, alertStream)
+	webhooksHandler := handlers.NewWebhooksHandler(webhooksService)
+	sinksHandler := handlers.NewSinksHandler(sinkManager)
 
 	router.GET("/health", healthHandler.GetHealthHandler)
 
-	router.POST("/events", eventsHandler.CreateEventsHTTPHandler)
+	router.POST("/events", handlers.DecompressRequest(cfg.Server.MaxDecompressedEventBytes), eventsHandler.CreateEventsHTTPHandler)
 	router.GET("/ws/events", eventsHandler.CreateEventsWebSocketHandler)
+	router.POST("/events/import", handlers.DecompressRequest(cfg.Server.MaxDecompressedImportBytes), importsHandler.ImportEventsHandler)
+	router.GET("/events/import/:job_id", importsHandler.GetImportHandler)
+	router.GET("/events/import/:job_id/errors", importsHandler.GetImportErrorsHandler)
+	router.GET("/events/export", handlers.CompressResponse(), exportsHandler.ExportEventsHandler)
+	router.POST("/events/export/jobs", handlers.CompressResponse(), exportsHandler.CreateExportJobHandler)
+	router.GET("/events/export/jobs", handlers.CompressResponse(), exportsHandler.ListExportJobsHandler)
+	router.GET("/events/export/jobs/:id", handlers.CompressResponse(), exportsHandler.GetExportJobHandler)
+
+	router.POST("/v1/track", handlers.DecompressRequest(cfg.Server.MaxDecompressedEventBytes), segmentHandler.TrackHandler)
+	router.POST("/v1/page", handlers.DecompressRequest(cfg.Server.MaxDecompressedEventBytes), segmentHandler.PageHandler)
+	router.POST("/v1/identify", handlers.DecompressRequest(cfg.Server.MaxDecompressedEventBytes), segmentHandler.IdentifyHandler)
+	router.POST("/v1/alias", handlers.DecompressRequest(cfg.Server.MaxDecompressedEventBytes), segmentHandler.AliasHandler)
+	router.POST("/v1/batch", handlers.DecompressRequest(cfg.Server.MaxDecompressedEventBytes), segmentHandler.BatchHandler)
+
+	router.GET("/analytics", handlers.CompressResponse(), analyticsHandler.GetAnalyticsHandler)
+	router.POST("/analytics/funnels", analyticsHandler.CreateFunnelHandler)
+	router.GET("/analytics/retention", analyticsHandler.GetRetentionHandler)
+	router.GET("/analytics/top/:dimension", analyticsHandler.GetTopHandler)
+	router.GET("/ws/analytics", analyticsHandler.StreamAnalyticsWebSocketHandler)
+
+	router.GET("/users/:user_id", usersHandler.GetUserHandler)
+	router.GET("/users/:user_id/timeline", usersHandler.GetUserTimelineHandler)
+	router.POST("/users/:user_id/aliases", usersHandler.AliasUserHandler)
 
-	router.GET("/analytics", analyticsHandler.GetAnalyticsHandler)
+	router.GET("/alerts", alertsHandler.ListAlertsHandler)
+	router.GET("/alerts/rules", alertsHandler.ListAlertRulesHandler)
+	router.GET("/ws/alerts", alertsHandler.StreamAlertsWebSocketHandler)
+
+	router.POST("/webhooks", webhooksHandler.CreateWebhookHandler)
+	router.GET("/webhooks", webhooksHandler.ListWebhooksHandler)
+	router.GET("/webhooks/:id", webhooksHandler.GetWebhookHandler)
+	router.DELETE("/webhooks/:id", webhooksHandler.DeleteWebhookHandler)
+	router.GET("/webhooks/:id/dead-letters", webhooksHandler.ListDeadLettersHandler)
+	router.POST("/webhooks/:id/dead-letters/:delivery_id/redeliver", webhooksHandler.RedeliverHandler)
+
+	router.GET("/admin/sinks", sinksHandler.ListSinksHandler)
+	router.POST("/admin/sinks/:name/pause", sinksHandler.PauseSinkHandler)
+	router.POST("/admin/sinks/:name/resume", sinksHandler.ResumeSinkHandler)
+
+	alertsCtx, stopAlerts := context.WithCancel(context.Background())
+	go alertsService.Run(alertsCtx)
 
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%s", cfg.Server.Port),
@@ -54,18 +131,55 @@ func main() {
 		}
 	}()
 
+	grpcServer := grpc.NewServer(grpcapi.ServerOptions()...)
+	eventsv1.RegisterEventServiceServer(grpcServer, grpcapi.NewEventServer(eventsService, analyticsService, eventStream))
+	if cfg.Server.GRPCPort != "" {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.Server.GRPCPort))
+		if err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				slog.Error("failed to start gRPC server", "error", err)
+				os.Exit(1)
+			}
+		}()
+	}
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-sigChan
 	slog.Info("Received terminate, graceful shutdown", "signal", sig)
+	stopAlerts()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	if err := sourceManager.Close(ctx); err != nil {
+		slog.Error("failed to close sources", "error", err)
+	}
 	if err := srv.Shutdown(ctx); err != nil {
 		slog.Error("Server Shutdown Failed", "error", err)
 		os.Exit(1)
 	}
+	// StreamEvents streams stay open until their clients leave, so stop
+	// waiting for them when ctx is done.
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+	if err := webhooksService.Close(ctx); err != nil {
+		slog.Error("failed to close webhooks", "error", err)
+	}
+	if err := sinkManager.Close(ctx); err != nil {
+		slog.Error("failed to close sinks", "error", err)
+	}
 	slog.Info("Server exited properly")
 
 	os.Exit(0)
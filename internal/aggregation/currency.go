@@ -0,0 +1,40 @@
+package aggregation
+
+import "strings"
+
+// CurrencyConverter converts purchase amounts into a base currency using a
+// static table of rates, each giving the value of one unit in the base
+// currency.
+type CurrencyConverter struct {
+	base  string
+	rates map[string]float64
+}
+
+func NewCurrencyConverter(base string, rates map[string]float64) *CurrencyConverter {
+	c := &CurrencyConverter{
+		base:  strings.ToUpper(base),
+		rates: make(map[string]float64, len(rates)),
+	}
+	for currency, rate := range rates {
+		c.rates[strings.ToUpper(currency)] = rate
+	}
+	return c
+}
+
+func (c *CurrencyConverter) Base() string {
+	return c.base
+}
+
+// Convert reports false for currencies without a configured rate. Amounts
+// without a currency are taken to be in the base currency already.
+func (c *CurrencyConverter) Convert(amount float64, currency string) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == "" || currency == c.base {
+		return amount, true
+	}
+	rate, ok := c.rates[currency]
+	if !ok {
+		return 0, false
+	}
+	return amount * rate, true
+}
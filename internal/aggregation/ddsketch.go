@@ -0,0 +1,182 @@
+package aggregation
+
+import (
+	"math"
+	"sort"
+)
+
+// DDSketchRelativeError bounds the relative error of DDSketch quantiles.
+const DDSketchRelativeError = 0.01
+
+var (
+	ddsGamma    = (1 + DDSketchRelativeError) / (1 - DDSketchRelativeError)
+	ddsLogGamma = math.Log(ddsGamma)
+)
+
+// DDSketch is a mergeable quantile sketch. Values fall into logarithmically
+// sized bins, so any quantile is within DDSketchRelativeError of the true
+// value. It also keeps exact moments, minimum and maximum. Minimum and
+// maximum can't be undone, so owners rebuild it on removal.
+type DDSketch struct {
+	positive   map[int]int
+	negative   map[int]int
+	zeros      int
+	count      int
+	sum        float64
+	sumSquares float64
+	min        float64
+	max        float64
+}
+
+func NewDDSketch() *DDSketch {
+	return &DDSketch{
+		positive: make(map[int]int),
+		negative: make(map[int]int),
+		min:      math.Inf(1),
+		max:      math.Inf(-1),
+	}
+}
+
+func (s *DDSketch) Add(value float64) {
+	switch {
+	case value > 0:
+		s.positive[ddsIndex(value)]++
+	case value < 0:
+		s.negative[ddsIndex(-value)]++
+	default:
+		s.zeros++
+	}
+	s.count++
+	s.sum += value
+	s.sumSquares += value * value
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+}
+
+func (s *DDSketch) Merge(other *DDSketch) {
+	for index, count := range other.positive {
+		s.positive[index] += count
+	}
+	for index, count := range other.negative {
+		s.negative[index] += count
+	}
+	s.zeros += other.zeros
+	s.count += other.count
+	s.sum += other.sum
+	s.sumSquares += other.sumSquares
+	s.min = math.Min(s.min, other.min)
+	s.max = math.Max(s.max, other.max)
+}
+
+func (s *DDSketch) Count() int {
+	return s.count
+}
+
+func (s *DDSketch) Min() float64 {
+	return s.min
+}
+
+func (s *DDSketch) Max() float64 {
+	return s.max
+}
+
+func (s *DDSketch) Mean() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.sum / float64(s.count)
+}
+
+// StdDev is the population standard deviation.
+func (s *DDSketch) StdDev() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	mean := s.Mean()
+	return math.Sqrt(math.Max(0, s.sumSquares/float64(s.count)-mean*mean))
+}
+
+// Quantile estimates the value at rank q*(count-1), for q in [0, 1].
+func (s *DDSketch) Quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	rank := int(q * float64(s.count-1))
+	var value float64
+	seen := 0
+	s.visit(func(v float64, count int) bool {
+		value = v
+		seen += count
+		return seen <= rank
+	})
+	return math.Max(s.min, math.Min(s.max, value))
+}
+
+// Histogram splits [min, max] into equal width buckets, counting each bin at
+// its representative value.
+func (s *DDSketch) Histogram(buckets int) []HistogramCount {
+	if s.count == 0 {
+		return nil
+	}
+	width := (s.max - s.min) / float64(buckets)
+	histogram := make([]HistogramCount, buckets)
+	for i := range histogram {
+		histogram[i].Start = s.min + float64(i)*width
+		histogram[i].End = s.min + float64(i+1)*width
+	}
+	histogram[buckets-1].End = s.max
+	s.visit(func(v float64, count int) bool {
+		i := buckets - 1
+		if width > 0 {
+			i = min(buckets-1, max(0, int((v-s.min)/width)))
+		}
+		histogram[i].Count += count
+		return true
+	})
+	return histogram
+}
+
+// HistogramCount is the number of values in [Start, End).
+type HistogramCount struct {
+	Start float64
+	End   float64
+	Count int
+}
+
+// visit calls fn with each bin's representative value and count in
+// ascending order until fn returns false.
+func (s *DDSketch) visit(fn func(value float64, count int) bool) {
+	negative := sortedIndexes(s.negative)
+	for i := len(negative) - 1; i >= 0; i-- {
+		if !fn(-ddsValue(negative[i]), s.negative[negative[i]]) {
+			return
+		}
+	}
+	if s.zeros > 0 && !fn(0, s.zeros) {
+		return
+	}
+	for _, index := range sortedIndexes(s.positive) {
+		if !fn(ddsValue(index), s.positive[index]) {
+			return
+		}
+	}
+}
+
+func ddsIndex(value float64) int {
+	return int(math.Ceil(math.Log(value) / ddsLogGamma))
+}
+
+// ddsValue is the point of bin index with the same relative distance to
+// both of its bounds.
+func ddsValue(index int) float64 {
+	return 2 * math.Pow(ddsGamma, float64(index)) / (ddsGamma + 1)
+}
+
+func sortedIndexes(bins map[int]int) []int {
+	indexes := make([]int, 0, len(bins))
+	for index := range bins {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
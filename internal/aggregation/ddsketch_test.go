@@ -0,0 +1,57 @@
+package aggregation
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDDSketch_Quantile(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	a := NewDDSketch()
+	b := NewDDSketch()
+	values := make([]float64, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		value := math.Exp(rng.NormFloat64()*2) * 50
+		if i%100 == 0 {
+			value = -value
+		}
+		values = append(values, value)
+		if i%2 == 0 {
+			a.Add(value)
+		} else {
+			b.Add(value)
+		}
+	}
+	a.Merge(b)
+	sort.Float64s(values)
+
+	assert.Equal(t, len(values), a.Count())
+	assert.Equal(t, values[0], a.Min())
+	assert.Equal(t, values[len(values)-1], a.Max())
+	for _, q := range []float64{0, 0.005, 0.25, 0.5, 0.9, 0.95, 0.99, 1} {
+		expected := values[int(q*float64(len(values)-1))]
+		assert.InDelta(t, expected, a.Quantile(q), math.Abs(expected)*DDSketchRelativeError+1e-9, "q=%v", q)
+	}
+}
+
+func TestDDSketch_Moments(t *testing.T) {
+	sketch := NewDDSketch()
+	for _, value := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		sketch.Add(value)
+	}
+
+	assert.Equal(t, 5.0, sketch.Mean())
+	assert.InDelta(t, 2.0, sketch.StdDev(), 1e-9)
+
+	histogram := sketch.Histogram(7)
+	counts := make([]int, len(histogram))
+	for i, bucket := range histogram {
+		counts[i] = bucket.Count
+	}
+	assert.Equal(t, []int{1, 0, 3, 2, 0, 1, 1}, counts)
+	assert.Equal(t, 9.0, histogram[6].End)
+}
@@ -0,0 +1,108 @@
+package aggregation
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+)
+
+const (
+	hllPrecision = 14
+	hllRegisters = 1 << hllPrecision
+
+	// Sketches stay sparse until this many registers are set, which keeps the
+	// many small per-minute buckets cheap.
+	hllSparseLimit = hllRegisters / 8
+)
+
+// HLLRelativeError is the relative standard error of a HyperLogLog estimate
+// at the precision used here.
+var HLLRelativeError = 1.04 / math.Sqrt(hllRegisters)
+
+var hllSeed = maphash.MakeSeed()
+
+// HyperLogLog is a mergeable approximate distinct counter. It cannot forget
+// values, so owners rebuild it when something is removed.
+type HyperLogLog struct {
+	sparse map[uint16]uint8
+	dense  []uint8
+}
+
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{sparse: make(map[uint16]uint8)}
+}
+
+func (h *HyperLogLog) Add(value string) {
+	hash := maphash.String(hllSeed, value)
+	index := uint16(hash >> (64 - hllPrecision))
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1)) + 1)
+	h.set(index, rank)
+}
+
+func (h *HyperLogLog) set(index uint16, rank uint8) {
+	if h.dense != nil {
+		if rank > h.dense[index] {
+			h.dense[index] = rank
+		}
+		return
+	}
+	if rank > h.sparse[index] {
+		h.sparse[index] = rank
+		if len(h.sparse) > hllSparseLimit {
+			h.densify()
+		}
+	}
+}
+
+func (h *HyperLogLog) densify() {
+	h.dense = make([]uint8, hllRegisters)
+	for index, rank := range h.sparse {
+		h.dense[index] = rank
+	}
+	h.sparse = nil
+}
+
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	if other.dense == nil {
+		for index, rank := range other.sparse {
+			h.set(index, rank)
+		}
+		return
+	}
+	if h.dense == nil {
+		h.densify()
+	}
+	for index, rank := range other.dense {
+		if rank > h.dense[index] {
+			h.dense[index] = rank
+		}
+	}
+}
+
+func (h *HyperLogLog) Count() int {
+	m := float64(hllRegisters)
+	sum := 0.0
+	zeros := 0
+	if h.dense != nil {
+		for _, rank := range h.dense {
+			sum += math.Ldexp(1, -int(rank))
+			if rank == 0 {
+				zeros++
+			}
+		}
+	} else {
+		zeros = hllRegisters - len(h.sparse)
+		sum = float64(zeros)
+		for _, rank := range h.sparse {
+			sum += math.Ldexp(1, -int(rank))
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Linear counting is far more accurate for small cardinalities.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int(math.Round(estimate))
+}
@@ -0,0 +1,47 @@
+package aggregation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHyperLogLog_Count(t *testing.T) {
+	tests := []struct {
+		name     string
+		distinct int
+	}{
+		{name: "empty", distinct: 0},
+		{name: "sparse", distinct: 100},
+		{name: "dense", distinct: 50000},
+		{name: "large", distinct: 500000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hll := NewHyperLogLog()
+			for i := 0; i < tt.distinct; i++ {
+				value := fmt.Sprintf("user-%d", i)
+				hll.Add(value)
+				hll.Add(value)
+			}
+			tolerance := 3 * HLLRelativeError * float64(tt.distinct)
+			assert.InDelta(t, tt.distinct, hll.Count(), tolerance+1)
+		})
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	a := NewHyperLogLog()
+	b := NewHyperLogLog()
+	for i := 0; i < 30000; i++ {
+		a.Add(fmt.Sprintf("user-%d", i))
+		b.Add(fmt.Sprintf("user-%d", i+20000))
+	}
+
+	merged := NewHyperLogLog()
+	merged.Merge(a)
+	merged.Merge(b)
+	assert.InDelta(t, 50000, merged.Count(), 3*HLLRelativeError*50000)
+}
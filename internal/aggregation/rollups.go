@@ -0,0 +1,544 @@
+package aggregation
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// Rollups maintains per-minute and per-hour aggregates of stored events so
+// window queries can be answered by merging buckets instead of rescanning
+// every event. It is kept up to date as a storage.EventObserver.
+type Rollups struct {
+	mutex      sync.RWMutex
+	converter  *CurrencyConverter
+	minutes    map[int64]*bucket
+	hours      map[int64]*bucket
+	minuteKeys []int64
+	hourKeys   []int64
+}
+
+type bucket struct {
+	total  int
+	byType map[models.EventType]int
+	users  *HyperLogLog
+	top    map[models.TopDimension]*TopK
+	stats  map[string]*DDSketch
+
+	revenue     float64
+	orders      int
+	unconverted int
+	products    map[string]*ProductTotals
+
+	// Only minute buckets keep their events, to trim the partial minutes at
+	// the edges of a query.
+	events map[string]*models.Event
+}
+
+// Summary is the result of merging the buckets covering a time range.
+type Summary struct {
+	TotalEvents  int
+	EventsByType map[models.EventType]int
+	Users        *HyperLogLog
+	Hours        map[time.Time]int
+
+	Revenue           float64
+	Orders            int
+	UnconvertedOrders int
+	RevenueHours      map[time.Time]float64
+	Products          map[string]*ProductTotals
+}
+
+// ProductTotals is the revenue, in the base currency, and order count of a
+// single product.
+type ProductTotals struct {
+	Revenue float64
+	Orders  int
+}
+
+func NewRollups(converter *CurrencyConverter) *Rollups {
+	r := &Rollups{converter: converter}
+	r.reset()
+	return r
+}
+
+func (r *Rollups) reset() {
+	r.minutes = make(map[int64]*bucket)
+	r.hours = make(map[int64]*bucket)
+	r.minuteKeys = nil
+	r.hourKeys = nil
+}
+
+func (r *Rollups) EventSaved(event, previous *models.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if previous != nil {
+		r.remove(previous)
+	}
+	r.add(event)
+}
+
+func (r *Rollups) EventDeleted(event *models.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.remove(event)
+}
+
+func (r *Rollups) EventsCleared() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.reset()
+}
+
+func (r *Rollups) add(event *models.Event) {
+	if event.Timestamp == nil {
+		return
+	}
+	minuteKey := event.Timestamp.Truncate(time.Minute).Unix()
+	minute, ok := r.minutes[minuteKey]
+	if !ok {
+		minute = newBucket()
+		minute.events = make(map[string]*models.Event)
+		r.minutes[minuteKey] = minute
+		r.minuteKeys = insertKey(r.minuteKeys, minuteKey)
+	}
+	if minute.events[event.EventID] == event {
+		return
+	}
+	minute.events[event.EventID] = event
+	minute.add(event, r.converter)
+
+	hourKey := event.Timestamp.Truncate(time.Hour).Unix()
+	hour, ok := r.hours[hourKey]
+	if !ok {
+		hour = newBucket()
+		r.hours[hourKey] = hour
+		r.hourKeys = insertKey(r.hourKeys, hourKey)
+	}
+	hour.add(event, r.converter)
+}
+
+func (r *Rollups) remove(event *models.Event) {
+	if event.Timestamp == nil {
+		return
+	}
+	minuteKey := event.Timestamp.Truncate(time.Minute).Unix()
+	minute, ok := r.minutes[minuteKey]
+	if !ok || minute.events[event.EventID] != event {
+		return
+	}
+	delete(minute.events, event.EventID)
+	if minute.remove(event, r.converter) {
+		delete(r.minutes, minuteKey)
+		r.minuteKeys = removeKey(r.minuteKeys, minuteKey)
+	} else {
+		minute.resetSketches()
+		for _, remaining := range minute.events {
+			minute.addToSketches(remaining)
+		}
+	}
+
+	hourKey := event.Timestamp.Truncate(time.Hour).Unix()
+	hour, ok := r.hours[hourKey]
+	if !ok {
+		return
+	}
+	if hour.remove(event, r.converter) {
+		delete(r.hours, hourKey)
+		r.hourKeys = removeKey(r.hourKeys, hourKey)
+		return
+	}
+	hour.resetSketches()
+	hourStart := hourKey * int64(time.Second)
+	for _, key := range keysInRange(r.minuteKeys, hourStart, hourStart+int64(time.Hour)) {
+		hour.mergeSketches(r.minutes[key])
+	}
+}
+
+// Query merges the buckets covering [start, end], both inclusive, matching
+// models.EventFilter semantics. A nil bound leaves that side open.
+func (r *Rollups) Query(start, end *time.Time) *Summary {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	summary := &Summary{
+		EventsByType: make(map[models.EventType]int),
+		Users:        NewHyperLogLog(),
+		Hours:        make(map[time.Time]int),
+		RevenueHours: make(map[time.Time]float64),
+		Products:     make(map[string]*ProductTotals),
+	}
+	from, to, ok := r.bounds(start, end)
+	if !ok {
+		return summary
+	}
+	r.walk(from, to, func(key int64, b *bucket) {
+		hour := time.Unix(key, 0).UTC().Truncate(time.Hour)
+		summary.Hours[hour] += b.total
+		if b.orders > 0 {
+			summary.RevenueHours[hour] += b.revenue
+		}
+		b.mergeInto(summary)
+	}, func(event *models.Event) {
+		hour := event.Timestamp.UTC().Truncate(time.Hour)
+		summary.TotalEvents++
+		summary.EventsByType[event.EventType]++
+		summary.Users.Add(event.UserID)
+		summary.Hours[hour]++
+		if amount, ok := r.revenueOf(event); ok {
+			summary.Revenue += amount
+			summary.Orders++
+			summary.RevenueHours[hour] += amount
+			addProduct(summary.Products, event.Properties.ProductID, amount, 1)
+		} else if event.EventType == models.EventTypePurchase {
+			summary.UnconvertedOrders++
+		}
+	})
+	return summary
+}
+
+// revenueOf returns the base currency amount of a purchase event, or false
+// for other events and purchases in a currency without a rate.
+func (r *Rollups) revenueOf(event *models.Event) (float64, bool) {
+	if event.EventType != models.EventTypePurchase {
+		return 0, false
+	}
+	return r.converter.Convert(event.Properties.Amount, event.Properties.Currency)
+}
+
+// Count returns the number of events in the half-open range [from, to).
+func (r *Rollups) Count(from, to time.Time) int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	end := to.Add(-time.Nanosecond)
+	lo, hi, ok := r.bounds(&from, &end)
+	if !ok {
+		return 0
+	}
+	count := 0
+	r.walk(lo, hi, func(_ int64, b *bucket) {
+		count += b.total
+	}, func(*models.Event) {
+		count++
+	})
+	return count
+}
+
+// CountType returns the number of events of one type in the half-open range
+// [from, to).
+func (r *Rollups) CountType(eventType models.EventType, from, to time.Time) int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	end := to.Add(-time.Nanosecond)
+	lo, hi, ok := r.bounds(&from, &end)
+	if !ok {
+		return 0
+	}
+	count := 0
+	r.walk(lo, hi, func(_ int64, b *bucket) {
+		count += b.byType[eventType]
+	}, func(event *models.Event) {
+		if event.EventType == eventType {
+			count++
+		}
+	})
+	return count
+}
+
+// walk visits the data in [from, to), given in unix nanoseconds, as whole
+// hour and minute buckets where possible and as individual events in the
+// partial minutes at either edge.
+func (r *Rollups) walk(from, to int64, visitBucket func(key int64, b *bucket), visitEvent func(*models.Event)) {
+	firstMinute := ceilTo(from, time.Minute)
+	lastMinute := floorTo(to, time.Minute)
+	if firstMinute > lastMinute {
+		// The whole range sits inside a single minute.
+		r.walkPartialMinute(floorTo(from, time.Minute), from, to, visitEvent)
+		return
+	}
+	if from < firstMinute {
+		r.walkPartialMinute(firstMinute-int64(time.Minute), from, firstMinute, visitEvent)
+	}
+	if lastMinute < to {
+		r.walkPartialMinute(lastMinute, lastMinute, to, visitEvent)
+	}
+
+	firstHour := ceilTo(firstMinute, time.Hour)
+	lastHour := floorTo(lastMinute, time.Hour)
+	if firstHour >= lastHour {
+		r.walkBuckets(r.minutes, r.minuteKeys, firstMinute, lastMinute, visitBucket)
+		return
+	}
+	r.walkBuckets(r.minutes, r.minuteKeys, firstMinute, firstHour, visitBucket)
+	r.walkBuckets(r.hours, r.hourKeys, firstHour, lastHour, visitBucket)
+	r.walkBuckets(r.minutes, r.minuteKeys, lastHour, lastMinute, visitBucket)
+}
+
+func (r *Rollups) walkBuckets(buckets map[int64]*bucket, keys []int64, from, to int64, visit func(key int64, b *bucket)) {
+	for _, key := range keysInRange(keys, from, to) {
+		visit(key, buckets[key])
+	}
+}
+
+func (r *Rollups) walkPartialMinute(minuteStart, from, to int64, visit func(*models.Event)) {
+	minute, ok := r.minutes[minuteStart/int64(time.Second)]
+	if !ok {
+		return
+	}
+	for _, event := range minute.events {
+		if ts := event.Timestamp.UnixNano(); ts >= from && ts < to {
+			visit(event)
+		}
+	}
+}
+
+// Top merges the heavy-hitter sketches for dimension covering [start, end],
+// both inclusive. Events in the partial minutes at the edges are added one
+// by one.
+func (r *Rollups) Top(dimension models.TopDimension, start, end *time.Time) *TopK {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	top := NewTopK()
+	from, to, ok := r.bounds(start, end)
+	if !ok {
+		return top
+	}
+	r.walk(from, to, func(_ int64, b *bucket) {
+		if sketch, ok := b.top[dimension]; ok {
+			top.Merge(sketch)
+		}
+	}, func(event *models.Event) {
+		if key, ok := dimension.Key(event); ok {
+			top.Add(key)
+		}
+	})
+	return top
+}
+
+// Stats merges the quantile sketches for a numeric property covering
+// [start, end], both inclusive.
+func (r *Rollups) Stats(field string, start, end *time.Time) *DDSketch {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stats := NewDDSketch()
+	from, to, ok := r.bounds(start, end)
+	if !ok {
+		return stats
+	}
+	r.walk(from, to, func(_ int64, b *bucket) {
+		if sketch, ok := b.stats[field]; ok {
+			stats.Merge(sketch)
+		}
+	}, func(event *models.Event) {
+		if value, ok := event.NumericProperty(field); ok {
+			stats.Add(value)
+		}
+	})
+	return stats
+}
+
+// DistinctUsers counts the users in [start, end] exactly by walking every
+// event in range, so its cost grows with the number of events.
+func (r *Rollups) DistinctUsers(start, end *time.Time) int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	from, to, ok := r.bounds(start, end)
+	if !ok {
+		return 0
+	}
+	users := make(map[string]struct{})
+	for _, key := range keysInRange(r.minuteKeys, floorTo(from, time.Minute), to) {
+		for _, event := range r.minutes[key].events {
+			if ts := event.Timestamp.UnixNano(); ts >= from && ts < to {
+				users[event.UserID] = struct{}{}
+			}
+		}
+	}
+	return len(users)
+}
+
+// bounds converts an inclusive [start, end] range into unix nanoseconds over
+// the half-open range [from, to), clamped to the stored data.
+func (r *Rollups) bounds(start, end *time.Time) (int64, int64, bool) {
+	if len(r.minuteKeys) == 0 {
+		return 0, 0, false
+	}
+	from := r.minuteKeys[0] * int64(time.Second)
+	to := (r.minuteKeys[len(r.minuteKeys)-1] + 60) * int64(time.Second)
+	if start != nil && start.UnixNano() > from {
+		from = start.UnixNano()
+	}
+	if end != nil && end.UnixNano()+1 < to {
+		to = end.UnixNano() + 1
+	}
+	return from, to, from < to
+}
+
+func newBucket() *bucket {
+	b := &bucket{
+		byType:   make(map[models.EventType]int),
+		products: make(map[string]*ProductTotals),
+	}
+	b.resetSketches()
+	return b
+}
+
+func (b *bucket) add(event *models.Event, converter *CurrencyConverter) {
+	b.total++
+	b.byType[event.EventType]++
+	b.addToSketches(event)
+	b.addPurchase(event, converter, 1)
+}
+
+func (b *bucket) resetSketches() {
+	b.users = NewHyperLogLog()
+	b.top = make(map[models.TopDimension]*TopK)
+	b.stats = make(map[string]*DDSketch)
+}
+
+func (b *bucket) addToSketches(event *models.Event) {
+	b.users.Add(event.UserID)
+	for _, dimension := range models.TopDimensions {
+		if key, ok := dimension.Key(event); ok {
+			b.topSketch(dimension).Add(key)
+		}
+	}
+	for _, field := range models.NumericProperties {
+		if value, ok := event.NumericProperty(field); ok {
+			b.statsSketch(field).Add(value)
+		}
+	}
+}
+
+func (b *bucket) mergeSketches(other *bucket) {
+	b.users.Merge(other.users)
+	for dimension, sketch := range other.top {
+		b.topSketch(dimension).Merge(sketch)
+	}
+	for field, sketch := range other.stats {
+		b.statsSketch(field).Merge(sketch)
+	}
+}
+
+func (b *bucket) topSketch(dimension models.TopDimension) *TopK {
+	sketch, ok := b.top[dimension]
+	if !ok {
+		sketch = NewTopK()
+		b.top[dimension] = sketch
+	}
+	return sketch
+}
+
+func (b *bucket) statsSketch(field string) *DDSketch {
+	sketch, ok := b.stats[field]
+	if !ok {
+		sketch = NewDDSketch()
+		b.stats[field] = sketch
+	}
+	return sketch
+}
+
+// remove reports whether the bucket is now empty. The caller rebuilds the
+// sketches otherwise.
+func (b *bucket) remove(event *models.Event, converter *CurrencyConverter) bool {
+	b.total--
+	b.byType[event.EventType]--
+	if b.byType[event.EventType] <= 0 {
+		delete(b.byType, event.EventType)
+	}
+	b.addPurchase(event, converter, -1)
+	return b.total <= 0
+}
+
+// addPurchase adds (sign 1) or subtracts (sign -1) a purchase's revenue.
+func (b *bucket) addPurchase(event *models.Event, converter *CurrencyConverter, sign int) {
+	if event.EventType != models.EventTypePurchase {
+		return
+	}
+	amount, ok := converter.Convert(event.Properties.Amount, event.Properties.Currency)
+	if !ok {
+		b.unconverted += sign
+		return
+	}
+	b.revenue += float64(sign) * amount
+	b.orders += sign
+	addProduct(b.products, event.Properties.ProductID, float64(sign)*amount, sign)
+	if b.orders == 0 {
+		// Don't let floating point drift leave revenue without orders.
+		b.revenue = 0
+	}
+}
+
+func addProduct(products map[string]*ProductTotals, productID string, revenue float64, orders int) {
+	totals, ok := products[productID]
+	if !ok {
+		totals = &ProductTotals{}
+		products[productID] = totals
+	}
+	totals.Revenue += revenue
+	totals.Orders += orders
+	if totals.Orders <= 0 {
+		delete(products, productID)
+	}
+}
+
+func (b *bucket) mergeInto(summary *Summary) {
+	summary.TotalEvents += b.total
+	for eventType, count := range b.byType {
+		summary.EventsByType[eventType] += count
+	}
+	summary.Users.Merge(b.users)
+	summary.Revenue += b.revenue
+	summary.Orders += b.orders
+	summary.UnconvertedOrders += b.unconverted
+	for productID, totals := range b.products {
+		addProduct(summary.Products, productID, totals.Revenue, totals.Orders)
+	}
+}
+
+// keysInRange returns the sorted bucket keys (unix seconds) whose start lies
+// in [from, to), given in unix nanoseconds.
+func keysInRange(keys []int64, from, to int64) []int64 {
+	lo := sort.Search(len(keys), func(i int) bool { return keys[i]*int64(time.Second) >= from })
+	hi := sort.Search(len(keys), func(i int) bool { return keys[i]*int64(time.Second) >= to })
+	return keys[lo:hi]
+}
+
+func insertKey(keys []int64, key int64) []int64 {
+	i := sort.Search(len(keys), func(i int) bool { return keys[i] >= key })
+	keys = append(keys, 0)
+	copy(keys[i+1:], keys[i:])
+	keys[i] = key
+	return keys
+}
+
+func removeKey(keys []int64, key int64) []int64 {
+	i := sort.Search(len(keys), func(i int) bool { return keys[i] >= key })
+	if i < len(keys) && keys[i] == key {
+		keys = append(keys[:i], keys[i+1:]...)
+	}
+	return keys
+}
+
+func floorTo(nanos int64, d time.Duration) int64 {
+	n := int64(d)
+	if nanos%n < 0 {
+		return nanos - nanos%n - n
+	}
+	return nanos - nanos%n
+}
+
+func ceilTo(nanos int64, d time.Duration) int64 {
+	floor := floorTo(nanos, d)
+	if floor == nanos {
+		return floor
+	}
+	return floor + int64(d)
+}
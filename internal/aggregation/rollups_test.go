@@ -0,0 +1,132 @@
+package aggregation
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var eventTypes = []models.EventType{
+	models.EventTypePageView,
+	models.EventTypeClick,
+	models.EventTypePurchase,
+	models.EventTypeSignup,
+}
+
+func TestRollups_QueryMatchesScan(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC)
+	converter := NewCurrencyConverter("USD", map[string]float64{"EUR": 1.1})
+	rollups := NewRollups(converter)
+	currencies := []string{"", "USD", "EUR", "JPY"}
+
+	events := make([]*models.Event, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		ts := base.Add(time.Duration(rng.Int63n(int64(72 * time.Hour))))
+		event := &models.Event{
+			EventID:   fmt.Sprintf("event-%d", i),
+			UserID:    fmt.Sprintf("user-%d", rng.Intn(50)),
+			EventType: eventTypes[rng.Intn(len(eventTypes))],
+			Timestamp: &ts,
+		}
+		if event.EventType == models.EventTypePurchase {
+			event.Properties = models.EventProperties{
+				Amount:    float64(rng.Intn(10000)) / 100,
+				ProductID: fmt.Sprintf("product-%d", rng.Intn(5)),
+				Currency:  currencies[rng.Intn(len(currencies))],
+			}
+		}
+		events = append(events, event)
+		rollups.EventSaved(event, nil)
+	}
+
+	// Overwrite and delete a few so removal paths are exercised too.
+	for i := 0; i < 100; i++ {
+		previous := events[i]
+		ts := previous.Timestamp.Add(90 * time.Minute)
+		replacement := &models.Event{
+			EventID:   previous.EventID,
+			UserID:    "replacement",
+			EventType: models.EventTypeSignup,
+			Timestamp: &ts,
+		}
+		events[i] = replacement
+		rollups.EventSaved(replacement, previous)
+	}
+	for _, event := range events[100:150] {
+		rollups.EventDeleted(event)
+	}
+	events = append(events[:100], events[150:]...)
+
+	ranges := [][2]*time.Time{{nil, nil}}
+	for i := 0; i < 200; i++ {
+		start := base.Add(time.Duration(rng.Int63n(int64(80*time.Hour))) - 4*time.Hour)
+		end := start.Add(time.Duration(rng.Int63n(int64(30 * time.Hour))))
+		ranges = append(ranges, [2]*time.Time{&start, &end})
+	}
+	aligned := base.Add(5 * time.Hour)
+	alignedEnd := aligned.Add(24 * time.Hour)
+	ranges = append(ranges, [2]*time.Time{&aligned, &alignedEnd}, [2]*time.Time{&aligned, nil}, [2]*time.Time{nil, &alignedEnd})
+
+	for _, r := range ranges {
+		expected, users := scan(events, converter, &models.EventFilter{StartTimestamp: r[0], EndTimestamp: r[1]})
+		actual := rollups.Query(r[0], r[1])
+		assert.Equal(t, expected.TotalEvents, actual.TotalEvents, "range %v - %v", r[0], r[1])
+		assert.Equal(t, expected.EventsByType, actual.EventsByType, "range %v - %v", r[0], r[1])
+		assert.Equal(t, expected.Hours, actual.Hours, "range %v - %v", r[0], r[1])
+		assert.Equal(t, len(users), rollups.DistinctUsers(r[0], r[1]), "range %v - %v", r[0], r[1])
+		assert.InDelta(t, len(users), actual.Users.Count(), 1, "range %v - %v", r[0], r[1])
+		assert.InDelta(t, expected.Revenue, actual.Revenue, 1e-6, "range %v - %v", r[0], r[1])
+		assert.Equal(t, expected.Orders, actual.Orders, "range %v - %v", r[0], r[1])
+		assert.Equal(t, expected.UnconvertedOrders, actual.UnconvertedOrders, "range %v - %v", r[0], r[1])
+		assert.Equal(t, len(expected.Products), len(actual.Products), "range %v - %v", r[0], r[1])
+		for productID, totals := range expected.Products {
+			assert.Equal(t, totals.Orders, actual.Products[productID].Orders, "range %v - %v", r[0], r[1])
+			assert.InDelta(t, totals.Revenue, actual.Products[productID].Revenue, 1e-6, "range %v - %v", r[0], r[1])
+		}
+	}
+}
+
+func TestRollups_Cleared(t *testing.T) {
+	rollups := NewRollups(NewCurrencyConverter("USD", nil))
+	ts := time.Now()
+	rollups.EventSaved(&models.Event{EventID: "1", UserID: "123", EventType: models.EventTypeClick, Timestamp: &ts}, nil)
+	assert.Equal(t, 1, rollups.Query(nil, nil).TotalEvents)
+
+	rollups.EventsCleared()
+	assert.Equal(t, 0, rollups.Query(nil, nil).TotalEvents)
+}
+
+func scan(events []*models.Event, converter *CurrencyConverter, filter *models.EventFilter) (*Summary, map[string]struct{}) {
+	users := make(map[string]struct{})
+	summary := &Summary{
+		EventsByType: make(map[models.EventType]int),
+		Hours:        make(map[time.Time]int),
+		Products:     make(map[string]*ProductTotals),
+	}
+	for _, event := range events {
+		if !event.MatchesFilter(filter) {
+			continue
+		}
+		summary.TotalEvents++
+		summary.EventsByType[event.EventType]++
+		users[event.UserID] = struct{}{}
+		summary.Hours[event.Timestamp.Truncate(time.Hour)]++
+		if event.EventType != models.EventTypePurchase {
+			continue
+		}
+		amount, ok := converter.Convert(event.Properties.Amount, event.Properties.Currency)
+		if !ok {
+			summary.UnconvertedOrders++
+			continue
+		}
+		summary.Revenue += amount
+		summary.Orders++
+		addProduct(summary.Products, event.Properties.ProductID, amount, 1)
+	}
+	return summary, users
+}
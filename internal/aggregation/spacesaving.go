@@ -0,0 +1,159 @@
+package aggregation
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// topKCapacity is the number of keys each heavy-hitter sketch tracks. It is
+// several times the largest limit a top-N query accepts, so that keys near
+// the bottom of the largest answer are still tracked once the sketch is
+// full, rather than being the ones it evicts.
+const topKCapacity = 10 * models.MaxTopLimit
+
+// TopK is a mergeable Space-Saving heavy-hitter sketch. While it tracks
+// every key it has seen its counts are exact; once full, the smallest key is
+// replaced and counts may overestimate by up to their error. Like
+// HyperLogLog it cannot forget keys, so owners rebuild it on removal.
+type TopK struct {
+	counters map[string]*topCounter
+	// smallest orders the counters so the one to replace is found quickly.
+	smallest topHeap
+	// missing bounds the count of any key that isn't tracked.
+	missing int
+}
+
+type topCounter struct {
+	key   string
+	count int
+	error int
+	index int
+}
+
+// TopKItem is a key with its estimated count. The true count lies in
+// [Count-Error, Count].
+type TopKItem struct {
+	Key   string
+	Count int
+	Error int
+}
+
+func NewTopK() *TopK {
+	return &TopK{counters: make(map[string]*topCounter)}
+}
+
+func (t *TopK) Add(key string) {
+	if counter, ok := t.counters[key]; ok {
+		counter.count++
+		heap.Fix(&t.smallest, counter.index)
+		return
+	}
+	if len(t.counters) < topKCapacity {
+		counter := &topCounter{key: key, count: 1}
+		t.counters[key] = counter
+		heap.Push(&t.smallest, counter)
+		return
+	}
+	// Replace the smallest key, reusing its counter.
+	min := t.smallest[0]
+	delete(t.counters, min.key)
+	t.missing = max(t.missing, min.count)
+	min.key = key
+	min.error = min.count
+	min.count++
+	t.counters[key] = min
+	heap.Fix(&t.smallest, 0)
+}
+
+// Merge folds other into t. A key tracked by only one side may have been
+// dropped by the other, so it is credited with the other side's bound.
+func (t *TopK) Merge(other *TopK) {
+	for key, counter := range t.counters {
+		if _, ok := other.counters[key]; !ok {
+			counter.count += other.missing
+			counter.error += other.missing
+		}
+	}
+	for key, counter := range other.counters {
+		if mine, ok := t.counters[key]; ok {
+			mine.count += counter.count
+			mine.error += counter.error
+		} else {
+			mine := &topCounter{
+				key:   key,
+				count: counter.count + t.missing,
+				error: counter.error + t.missing,
+			}
+			t.counters[key] = mine
+			t.smallest = append(t.smallest, mine)
+		}
+	}
+	t.missing += other.missing
+	for i, counter := range t.smallest {
+		counter.index = i
+	}
+	heap.Init(&t.smallest)
+	for len(t.counters) > topKCapacity {
+		min := heap.Pop(&t.smallest).(*topCounter)
+		delete(t.counters, min.key)
+		t.missing = max(t.missing, min.count)
+	}
+}
+
+// Exact reports whether every count is exact.
+func (t *TopK) Exact() bool {
+	return t.missing == 0
+}
+
+// Top returns up to limit keys by descending count, ties broken by key.
+func (t *TopK) Top(limit int) []TopKItem {
+	items := make([]TopKItem, 0, len(t.counters))
+	for key, counter := range t.counters {
+		items = append(items, TopKItem{Key: key, Count: counter.count, Error: counter.error})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Count != items[j].Count {
+			return items[i].Count > items[j].Count
+		}
+		return items[i].Key < items[j].Key
+	})
+	if len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
+
+// topHeap is a min-heap of counters by count. Of equal counts the largest
+// key comes first, so Top breaks the ties it keeps the same way.
+type topHeap []*topCounter
+
+func (h topHeap) Len() int { return len(h) }
+
+func (h topHeap) Less(i, j int) bool {
+	if h[i].count != h[j].count {
+		return h[i].count < h[j].count
+	}
+	return h[i].key > h[j].key
+}
+
+func (h topHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *topHeap) Push(x any) {
+	counter := x.(*topCounter)
+	counter.index = len(*h)
+	*h = append(*h, counter)
+}
+
+func (h *topHeap) Pop() any {
+	old := *h
+	counter := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return counter
+}
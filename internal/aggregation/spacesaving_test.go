@@ -0,0 +1,112 @@
+package aggregation
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopK_Exact(t *testing.T) {
+	top := NewTopK()
+	for i := 0; i < topKCapacity; i++ {
+		for j := 0; j <= i%5; j++ {
+			top.Add(fmt.Sprintf("page-%03d", i))
+		}
+	}
+
+	assert.True(t, top.Exact())
+	assert.Equal(t, []TopKItem{
+		{Key: "page-004", Count: 5},
+		{Key: "page-009", Count: 5},
+	}, top.Top(2))
+}
+
+func TestTopK_HeavyHitters(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	// A few heavy keys in a long tail of rare ones, split across sketches
+	// the way rollup buckets are.
+	truth := make(map[string]int)
+	sketches := make([]*TopK, 10)
+	for i := range sketches {
+		sketches[i] = NewTopK()
+		for j := 0; j < 5000; j++ {
+			key := fmt.Sprintf("tail-%d", rng.Intn(20000))
+			if rng.Intn(4) == 0 {
+				key = fmt.Sprintf("heavy-%d", rng.Intn(5))
+			}
+			sketches[i].Add(key)
+			truth[key]++
+		}
+	}
+	merged := NewTopK()
+	for _, sketch := range sketches {
+		merged.Merge(sketch)
+	}
+
+	assert.False(t, merged.Exact())
+	items := merged.Top(5)
+	require.Len(t, items, 5)
+	for _, item := range items {
+		assert.Contains(t, item.Key, "heavy-")
+		assert.LessOrEqual(t, truth[item.Key], item.Count)
+		assert.GreaterOrEqual(t, truth[item.Key], item.Count-item.Error)
+	}
+}
+
+func TestTopK_MergeExact(t *testing.T) {
+	a := NewTopK()
+	b := NewTopK()
+	a.Add("x")
+	a.Add("y")
+	b.Add("y")
+	b.Add("z")
+	a.Merge(b)
+
+	assert.True(t, a.Exact())
+	assert.Equal(t, []TopKItem{
+		{Key: "y", Count: 2},
+		{Key: "x", Count: 1},
+		{Key: "z", Count: 1},
+	}, a.Top(10))
+}
+
+func TestTopK_HeavyHittersAtMaxLimit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	// Zipf-like counts for as many keys as the largest query asks for, in a
+	// tail of rare keys many times the sketch's size. The smallest heavy
+	// count is above the sketch's error bound, events / topKCapacity.
+	truth := make(map[string]int)
+	var stream []string
+	for i := 0; i < models.MaxTopLimit; i++ {
+		key := fmt.Sprintf("heavy-%03d", i)
+		truth[key] = 20000 / (i + 1)
+		for j := 0; j < truth[key]; j++ {
+			stream = append(stream, key)
+		}
+	}
+	for i := 0; i < 50000; i++ {
+		key := fmt.Sprintf("tail-%d", rng.Intn(100*topKCapacity))
+		truth[key]++
+		stream = append(stream, key)
+	}
+	rng.Shuffle(len(stream), func(i, j int) { stream[i], stream[j] = stream[j], stream[i] })
+
+	top := NewTopK()
+	for _, key := range stream {
+		top.Add(key)
+	}
+
+	assert.False(t, top.Exact())
+	items := top.Top(models.MaxTopLimit)
+	require.Len(t, items, models.MaxTopLimit)
+	// Keys with close counts may swap places, but every heavy key is there.
+	for _, item := range items {
+		assert.Contains(t, item.Key, "heavy-")
+		assert.LessOrEqual(t, truth[item.Key], item.Count)
+		assert.GreaterOrEqual(t, truth[item.Key], item.Count-item.Error)
+	}
+}
@@ -0,0 +1,98 @@
+// Package codec encodes events in the wire formats clients can send them in:
+// JSON, protobuf and MessagePack.
+package codec
+
+import (
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// Codec encodes and decodes events in one wire format.
+type Codec interface {
+	// Name is the format's WebSocket subprotocol.
+	Name() string
+	ContentType() string
+	// Binary reports whether the format is sent in binary WebSocket frames.
+	Binary() bool
+	Marshal(event *models.Event) ([]byte, error)
+	Unmarshal(data []byte, event *models.Event) error
+}
+
+var (
+	JSON        Codec = jsonCodec{}
+	Protobuf    Codec = protobufCodec{}
+	MessagePack Codec = msgpackCodec{}
+)
+
+// codecs are the formats offered as WebSocket subprotocols. A client offering
+// several gets the first one it lists.
+var codecs = []Codec{Protobuf, MessagePack, JSON}
+
+// contentTypes adds the other names clients use for each format.
+var contentTypes = map[string]Codec{
+	"application/json":        JSON,
+	"application/x-protobuf":  Protobuf,
+	"application/protobuf":    Protobuf,
+	"application/msgpack":     MessagePack,
+	"application/x-msgpack":   MessagePack,
+	"application/vnd.msgpack": MessagePack,
+}
+
+// ForContentType returns the codec for a Content-Type header, ignoring its
+// parameters.
+func ForContentType(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	codec, ok := contentTypes[mediaType]
+	return codec, ok
+}
+
+// ForAccept returns the first codec named in an Accept header, or fallback
+// when it names none of them.
+func ForAccept(accept string, fallback Codec) Codec {
+	for _, part := range strings.Split(accept, ",") {
+		if codec, ok := ForContentType(strings.TrimSpace(part)); ok {
+			return codec
+		}
+	}
+	return fallback
+}
+
+// ForSubprotocol returns the codec for a negotiated WebSocket subprotocol,
+// JSON when none was.
+func ForSubprotocol(name string) Codec {
+	for _, codec := range codecs {
+		if codec.Name() == name {
+			return codec
+		}
+	}
+	return JSON
+}
+
+// Subprotocols are the WebSocket subprotocols a server can offer.
+func Subprotocols() []string {
+	names := make([]string, len(codecs))
+	for i, codec := range codecs {
+		names[i] = codec.Name()
+	}
+	return names
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string        { return "json" }
+func (jsonCodec) ContentType() string { return "application/json" }
+func (jsonCodec) Binary() bool        { return false }
+
+func (jsonCodec) Marshal(event *models.Event) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+func (jsonCodec) Unmarshal(data []byte, event *models.Event) error {
+	return json.Unmarshal(data, event)
+}
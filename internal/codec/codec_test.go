@@ -0,0 +1,59 @@
+package codec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	event := &models.Event{
+		EventID:   "1",
+		UserID:    "alice",
+		EventType: models.EventTypePurchase,
+		Timestamp: &timestamp,
+		Properties: models.EventProperties{
+			Amount:    9.99,
+			ProductID: "xyz",
+			Country:   "GB",
+			Currency:  "EUR",
+		},
+	}
+	for _, codec := range codecs {
+		t.Run(codec.Name(), func(t *testing.T) {
+			data, err := codec.Marshal(event)
+			require.NoError(t, err)
+			var decoded models.Event
+			require.NoError(t, codec.Unmarshal(data, &decoded))
+			assert.True(t, timestamp.Equal(*decoded.Timestamp))
+			decoded.Timestamp = event.Timestamp
+			assert.Equal(t, *event, decoded)
+		})
+	}
+}
+
+func TestMessagePack_JSONKeys(t *testing.T) {
+	var event models.Event
+	// {"event_id": "1", "properties": {"product_id": "xyz"}}
+	data := []byte("\x82\xa8event_id\xa11\xaaproperties\x81\xaaproduct_id\xa3xyz")
+	require.NoError(t, MessagePack.Unmarshal(data, &event))
+	assert.Equal(t, "1", event.EventID)
+	assert.Equal(t, "xyz", event.Properties.ProductID)
+}
+
+func TestNegotiation(t *testing.T) {
+	codec, ok := ForContentType("application/x-protobuf; charset=binary")
+	assert.True(t, ok)
+	assert.Equal(t, Protobuf, codec)
+	_, ok = ForContentType("text/plain")
+	assert.False(t, ok)
+
+	assert.Equal(t, MessagePack, ForAccept("text/html, application/msgpack", JSON))
+	assert.Equal(t, Protobuf, ForAccept("*/*", Protobuf))
+	assert.Equal(t, JSON, ForSubprotocol(""))
+	assert.Equal(t, []string{"protobuf", "msgpack", "json"}, Subprotocols())
+}
@@ -0,0 +1,32 @@
+package codec
+
+import (
+	"bytes"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// msgpackCodec encodes an event as a map with the same keys as its JSON.
+// The timestamp is a MessagePack timestamp.
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string        { return "msgpack" }
+func (msgpackCodec) ContentType() string { return "application/msgpack" }
+func (msgpackCodec) Binary() bool        { return true }
+
+func (msgpackCodec) Marshal(event *models.Event) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	if err := encoder.Encode(event); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(data []byte, event *models.Event) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(event)
+}
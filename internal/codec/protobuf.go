@@ -0,0 +1,95 @@
+package codec
+
+import (
+	eventsv1 "github.com/dnakolan/event-processing-service/api/events/v1"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var eventTypes = map[models.EventType]eventsv1.EventType{
+	models.EventTypePageView: eventsv1.EventType_EVENT_TYPE_PAGE_VIEW,
+	models.EventTypeClick:    eventsv1.EventType_EVENT_TYPE_CLICK,
+	models.EventTypePurchase: eventsv1.EventType_EVENT_TYPE_PURCHASE,
+	models.EventTypeSignup:   eventsv1.EventType_EVENT_TYPE_SIGNUP,
+	models.EventTypeCustom:   eventsv1.EventType_EVENT_TYPE_CUSTOM,
+}
+
+// EventTypeFromProto returns the empty EventType for one it doesn't know.
+func EventTypeFromProto(eventType eventsv1.EventType) models.EventType {
+	for t, pb := range eventTypes {
+		if pb == eventType {
+			return t
+		}
+	}
+	return ""
+}
+
+// EventFromProto leaves validation to the caller, so a nil or incomplete
+// event comes back with empty fields rather than an error.
+func EventFromProto(pb *eventsv1.Event) *models.Event {
+	event := &models.Event{
+		EventID:   pb.GetEventId(),
+		UserID:    pb.GetUserId(),
+		EventType: EventTypeFromProto(pb.GetEventType()),
+	}
+	if pb.GetTimestamp() != nil {
+		timestamp := pb.GetTimestamp().AsTime()
+		event.Timestamp = &timestamp
+	}
+	if p := pb.GetProperties(); p != nil {
+		event.Properties = models.EventProperties{
+			Page:      p.GetPage(),
+			Amount:    p.GetAmount(),
+			ProductID: p.GetProductId(),
+			Email:     p.GetEmail(),
+			Link:      p.GetLink(),
+			Country:   p.GetCountry(),
+			Currency:  p.GetCurrency(),
+			Name:      p.GetName(),
+		}
+	}
+	return event
+}
+
+func EventToProto(event *models.Event) *eventsv1.Event {
+	pb := &eventsv1.Event{
+		EventId:   event.EventID,
+		UserId:    event.UserID,
+		EventType: eventTypes[event.EventType],
+		Properties: &eventsv1.EventProperties{
+			Page:      event.Properties.Page,
+			Amount:    event.Properties.Amount,
+			ProductId: event.Properties.ProductID,
+			Email:     event.Properties.Email,
+			Link:      event.Properties.Link,
+			Country:   event.Properties.Country,
+			Currency:  event.Properties.Currency,
+			Name:      event.Properties.Name,
+		},
+	}
+	if event.Timestamp != nil {
+		pb.Timestamp = timestamppb.New(*event.Timestamp)
+	}
+	return pb
+}
+
+// protobufCodec speaks the events.v1.Event message of the gRPC API.
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string        { return "protobuf" }
+func (protobufCodec) ContentType() string { return "application/x-protobuf" }
+func (protobufCodec) Binary() bool        { return true }
+
+func (protobufCodec) Marshal(event *models.Event) ([]byte, error) {
+	return proto.Marshal(EventToProto(event))
+}
+
+func (protobufCodec) Unmarshal(data []byte, event *models.Event) error {
+	var pb eventsv1.Event
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	*event = *EventFromProto(&pb)
+	return nil
+}
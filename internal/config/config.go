@@ -1,34 +1,190 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"time"
 
+	"github.com/dnakolan/event-processing-service/internal/models"
 	"gopkg.in/yaml.v3"
 )
 
 var readFile = os.ReadFile
 
 type Config struct {
-	Server ServerConfig `yaml:"server"`
+	Server    ServerConfig    `yaml:"server"`
+	Analytics AnalyticsConfig `yaml:"analytics"`
+	Alerts    AlertsConfig    `yaml:"alerts"`
+	Webhooks  WebhooksConfig  `yaml:"webhooks"`
+	Sinks     []SinkConfig    `yaml:"sinks"`
+	Sources   []SourceConfig  `yaml:"sources"`
+	Exports   ExportsConfig   `yaml:"exports"`
+	Segment   SegmentConfig   `yaml:"segment"`
 }
 
+// ServerConfig sets the ports for the HTTP API and, unless GRPCPort is
+// empty, the gRPC API. The decompressed limits cap how far a compressed
+// request body to POST /events or the import endpoint may expand.
 type ServerConfig struct {
-	Port    string `yaml:"port"`
-	GinMode string `yaml:"gin_mode"`
+	Port     string `yaml:"port"`
+	GRPCPort string `yaml:"grpc_port"`
+	GinMode  string `yaml:"gin_mode"`
+
+	MaxDecompressedEventBytes  int64 `yaml:"max_decompressed_event_bytes"`
+	MaxDecompressedImportBytes int64 `yaml:"max_decompressed_import_bytes"`
+}
+
+type AnalyticsConfig struct {
+	PushInterval     time.Duration      `yaml:"push_interval"`
+	ExactUniqueLimit int                `yaml:"exact_unique_limit"`
+	ExactTopLimit    int                `yaml:"exact_top_limit"`
+	BaseCurrency     string             `yaml:"base_currency"`
+	ExchangeRates    map[string]float64 `yaml:"exchange_rates"`
+	SessionGap       time.Duration      `yaml:"session_gap"`
+}
+
+type AlertsConfig struct {
+	Interval   time.Duration      `yaml:"interval"`
+	WebhookURL string             `yaml:"webhook_url"`
+	Rules      []models.AlertRule `yaml:"rules"`
+}
+
+// WebhooksConfig controls delivery to webhook subscriptions. A failed
+// delivery is retried after InitialBackoff, doubling up to MaxBackoff, until
+// it has been attempted MaxAttempts times. Each subscription buffers up to
+// BufferSize events waiting for delivery and drops the rest.
+type WebhooksConfig struct {
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	Timeout        time.Duration `yaml:"timeout"`
+	BufferSize     int           `yaml:"buffer_size"`
+}
+
+// SinkConfig configures one sink that stored events are forwarded to. Type
+// is file, stdout, http or kafka; the other fields apply to the types noted, and
+// zero values mean the defaults.
+type SinkConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+
+	BatchSize      int           `yaml:"batch_size"`
+	FlushInterval  time.Duration `yaml:"flush_interval"`
+	BufferSize     int           `yaml:"buffer_size"`
+	MaxAttempts    int           `yaml:"max_attempts"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+
+	// file
+	Directory string        `yaml:"directory"`
+	MaxBytes  int64         `yaml:"max_bytes"`
+	MaxAge    time.Duration `yaml:"max_age"`
+	Gzip      bool          `yaml:"gzip"`
+
+	// http
+	URL string `yaml:"url"`
+
+	// http and kafka
+	Timeout time.Duration `yaml:"timeout"`
+
+	// kafka
+	Brokers     []string `yaml:"brokers"`
+	Topic       string   `yaml:"topic"`
+	Compression string   `yaml:"compression"`
+}
+
+// SourceConfig configures one message bus that events are consumed from.
+// Type is kafka; zero durations mean the defaults.
+type SourceConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+
+	// kafka
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+	Group   string   `yaml:"group"`
+}
+
+// ExportsConfig sets the directory export jobs write their files to.
+type ExportsConfig struct {
+	Directory string `yaml:"directory"`
+}
+
+// SegmentConfig maps the event names of Segment track calls to event types.
+// Names configured here are added to the defaults, which cover the Segment
+// spec events that correspond to one.
+type SegmentConfig struct {
+	TrackEvents map[string]models.EventType `yaml:"track_events"`
 }
 
 func NewConfig() (*Config, error) {
-	var cfg *Config
+	cfg := &Config{
+		Server: ServerConfig{
+			MaxDecompressedEventBytes:  1 << 20,
+			MaxDecompressedImportBytes: 1 << 30,
+		},
+		Analytics: AnalyticsConfig{
+			PushInterval:     5 * time.Second,
+			ExactUniqueLimit: 100000,
+			ExactTopLimit:    100000,
+			BaseCurrency:     "USD",
+			SessionGap:       30 * time.Minute,
+		},
+		Alerts: AlertsConfig{
+			Interval: time.Minute,
+		},
+		Webhooks: WebhooksConfig{
+			MaxAttempts:    8,
+			InitialBackoff: time.Second,
+			MaxBackoff:     5 * time.Minute,
+			Timeout:        10 * time.Second,
+			BufferSize:     10000,
+		},
+		Exports: ExportsConfig{
+			Directory: "data/exports",
+		},
+		Segment: SegmentConfig{
+			TrackEvents: map[string]models.EventType{
+				"Order Completed": models.EventTypePurchase,
+				"Signed Up":       models.EventTypeSignup,
+				"Product Clicked": models.EventTypeClick,
+				"Link Clicked":    models.EventTypeClick,
+			},
+		},
+	}
 
 	yamlFile, err := readFile("config.yaml")
 	if err != nil {
 		return nil, err
 	}
 
-	err = yaml.Unmarshal(yamlFile, &cfg)
+	err = yaml.Unmarshal(yamlFile, cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.Alerts.Interval <= 0 {
+		return nil, fmt.Errorf("alerts interval must be greater than 0")
+	}
+	for name, eventType := range cfg.Segment.TrackEvents {
+		if !eventType.Valid() {
+			return nil, fmt.Errorf("segment track event %q: invalid event_type %q", name, eventType)
+		}
+	}
+	// Alerts are tracked by rule name, so names must be unique.
+	ruleNames := make(map[string]bool, len(cfg.Alerts.Rules))
+	for _, rule := range cfg.Alerts.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("alert rule %q: %w", rule.Name, err)
+		}
+		if ruleNames[rule.Name] {
+			return nil, fmt.Errorf("alert rule %q: duplicate name", rule.Name)
+		}
+		ruleNames[rule.Name] = true
+	}
+
 	return cfg, nil
 }
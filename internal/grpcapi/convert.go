@@ -0,0 +1,196 @@
+package grpcapi
+
+import (
+	"time"
+
+	eventsv1 "github.com/dnakolan/event-processing-service/api/events/v1"
+	"github.com/dnakolan/event-processing-service/internal/codec"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func fromProtoFilter(pb *eventsv1.EventFilter) *models.EventFilter {
+	filter := &models.EventFilter{}
+	if pb == nil {
+		return filter
+	}
+	if pb.UserId != nil {
+		userID := pb.GetUserId()
+		filter.UserID = &userID
+	}
+	if pb.GetEventType() != eventsv1.EventType_EVENT_TYPE_UNSPECIFIED {
+		eventType := codec.EventTypeFromProto(pb.GetEventType())
+		filter.EventType = &eventType
+	}
+	if pb.GetStartTimestamp() != nil {
+		start := pb.GetStartTimestamp().AsTime()
+		filter.StartTimestamp = &start
+	}
+	if pb.GetEndTimestamp() != nil {
+		end := pb.GetEndTimestamp().AsTime()
+		filter.EndTimestamp = &end
+	}
+	for _, predicate := range pb.GetProperties() {
+		op := models.PredicateOp(predicate.GetOp())
+		if op == "" {
+			op = models.PredicateOpEq
+		}
+		filter.Properties = append(filter.Properties, models.PropertyPredicate{
+			Field: predicate.GetField(),
+			Op:    op,
+			Value: predicate.GetValue(),
+		})
+	}
+	return filter
+}
+
+func toProtoAnalytics(analytics *models.Analytics) *eventsv1.Analytics {
+	pb := &eventsv1.Analytics{
+		TimeWindow:             analytics.TimeWindow,
+		Start:                  toProtoTime(analytics.Start),
+		End:                    toProtoTime(analytics.End),
+		TotalEvents:            int64(analytics.TotalEvents),
+		EventsByType:           make(map[string]int64, len(analytics.EventsByType)),
+		UniqueUsers:            int64(analytics.UniqueUsers),
+		UniqueUsersApproximate: analytics.UniqueUsersApproximate,
+		UniqueUsersError:       analytics.UniqueUsersError,
+		Granularity:            string(analytics.Granularity),
+		Series:                 toProtoSeries(analytics.Series),
+		GroupBy:                analytics.GroupBy,
+	}
+	for eventType, count := range analytics.EventsByType {
+		pb.EventsByType[string(eventType)] = int64(count)
+	}
+	for _, hour := range analytics.EventsPerHour {
+		pb.EventsPerHour = append(pb.EventsPerHour, &eventsv1.EventsPerHour{
+			Hour:  timestamppb.New(hour.Hour),
+			Count: int64(hour.Count),
+		})
+	}
+	for i := range analytics.Groups {
+		pb.Groups = append(pb.Groups, toProtoGroup(&analytics.Groups[i]))
+	}
+	if analytics.Other != nil {
+		pb.Other = toProtoGroup(analytics.Other)
+	}
+	if analytics.Revenue != nil {
+		pb.Revenue = toProtoRevenue(analytics.Revenue)
+	}
+	if s := analytics.Sessions; s != nil {
+		pb.Sessions = &eventsv1.SessionMetrics{
+			SessionGap:            s.SessionGap,
+			Sessions:              int64(s.Sessions),
+			MeanDurationSeconds:   s.MeanDurationSeconds,
+			MedianDurationSeconds: s.MedianDurationSeconds,
+			BounceRate:            s.BounceRate,
+			EventsPerSession:      s.EventsPerSession,
+			PagesPerSession:       s.PagesPerSession,
+		}
+	}
+	for i := range analytics.Stats {
+		pb.Stats = append(pb.Stats, toProtoStats(&analytics.Stats[i]))
+	}
+	if analytics.Comparison != nil {
+		pb.Comparison = toProtoComparison(analytics.Comparison)
+	}
+	return pb
+}
+
+func toProtoSeries(series []models.TimeBucket) []*eventsv1.TimeBucket {
+	var pb []*eventsv1.TimeBucket
+	for _, bucket := range series {
+		pb = append(pb, &eventsv1.TimeBucket{
+			Start: timestamppb.New(bucket.Start),
+			Count: int64(bucket.Count),
+		})
+	}
+	return pb
+}
+
+func toProtoGroup(group *models.AnalyticsGroup) *eventsv1.AnalyticsGroup {
+	return &eventsv1.AnalyticsGroup{
+		Key:         group.Key,
+		TotalEvents: int64(group.TotalEvents),
+		UniqueUsers: int64(group.UniqueUsers),
+		Series:      toProtoSeries(group.Series),
+	}
+}
+
+func toProtoRevenue(revenue *models.Revenue) *eventsv1.Revenue {
+	pb := &eventsv1.Revenue{
+		Currency:          revenue.Currency,
+		TotalRevenue:      revenue.TotalRevenue,
+		Orders:            int64(revenue.Orders),
+		AverageOrderValue: revenue.AverageOrderValue,
+		UnconvertedOrders: int64(revenue.UnconvertedOrders),
+	}
+	for _, hour := range revenue.RevenuePerHour {
+		pb.RevenuePerHour = append(pb.RevenuePerHour, &eventsv1.RevenuePerHour{
+			Hour:    timestamppb.New(hour.Hour),
+			Revenue: hour.Revenue,
+		})
+	}
+	for _, product := range revenue.TopProducts {
+		pb.TopProducts = append(pb.TopProducts, &eventsv1.ProductRevenue{
+			ProductId: product.ProductID,
+			Revenue:   product.Revenue,
+			Orders:    int64(product.Orders),
+		})
+	}
+	return pb
+}
+
+func toProtoStats(stats *models.PropertyStats) *eventsv1.PropertyStats {
+	pb := &eventsv1.PropertyStats{
+		Property:        stats.Property,
+		Count:           int64(stats.Count),
+		Min:             stats.Min,
+		Max:             stats.Max,
+		Mean:            stats.Mean,
+		Stddev:          stats.StdDev,
+		P50:             stats.P50,
+		P90:             stats.P90,
+		P95:             stats.P95,
+		P99:             stats.P99,
+		Approximate:     stats.Approximate,
+		PercentileError: stats.PercentileError,
+	}
+	for _, bucket := range stats.Histogram {
+		pb.Histogram = append(pb.Histogram, &eventsv1.HistogramBucket{
+			Start: bucket.Start,
+			End:   bucket.End,
+			Count: int64(bucket.Count),
+		})
+	}
+	return pb
+}
+
+func toProtoComparison(comparison *models.Comparison) *eventsv1.Comparison {
+	pb := &eventsv1.Comparison{
+		Compare:      string(comparison.Compare),
+		Start:        timestamppb.New(comparison.Start),
+		End:          timestamppb.New(comparison.End),
+		TotalEvents:  toProtoDelta(comparison.TotalEvents),
+		EventsByType: make(map[string]*eventsv1.MetricDelta, len(comparison.EventsByType)),
+		UniqueUsers:  toProtoDelta(comparison.UniqueUsers),
+	}
+	for eventType, delta := range comparison.EventsByType {
+		pb.EventsByType[string(eventType)] = toProtoDelta(delta)
+	}
+	return pb
+}
+
+func toProtoDelta(delta models.MetricDelta) *eventsv1.MetricDelta {
+	return &eventsv1.MetricDelta{
+		Previous:      int64(delta.Previous),
+		Delta:         int64(delta.Delta),
+		PercentChange: delta.PercentChange,
+	}
+}
+
+func toProtoTime(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
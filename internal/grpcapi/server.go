@@ -0,0 +1,239 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	eventsv1 "github.com/dnakolan/event-processing-service/api/events/v1"
+	"github.com/dnakolan/event-processing-service/internal/codec"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// rejectedBuffer is how many rejections a StreamEvents stream may queue
+// before its client has to read them to send more.
+const rejectedBuffer = 16
+
+// EventServer implements the gRPC EventService over the same services as the
+// HTTP handlers.
+type EventServer struct {
+	eventsv1.UnimplementedEventServiceServer
+	events    services.EventsService
+	analytics services.AnalyticsService
+	stream    *services.EventStream
+}
+
+// NewEventServer pushes the events published to stream to every
+// StreamEvents client, so it should be one of events' publishers.
+func NewEventServer(events services.EventsService, analytics services.AnalyticsService, stream *services.EventStream) *EventServer {
+	return &EventServer{
+		events:    events,
+		analytics: analytics,
+		stream:    stream,
+	}
+}
+
+func (s *EventServer) CreateEvents(ctx context.Context, req *eventsv1.CreateEventsRequest) (*eventsv1.CreateEventsResponse, error) {
+	country := countryFromMetadata(ctx)
+	resp := &eventsv1.CreateEventsResponse{}
+	for _, pb := range req.GetEvents() {
+		event, created, err := s.events.IngestEvent(ctx, &models.CreateEventRequest{Event: *codec.EventFromProto(pb)}, country)
+		var invalid *services.ValidationError
+		if errors.As(err, &invalid) {
+			resp.Results = append(resp.Results, &eventsv1.CreateEventResult{Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.Results = append(resp.Results, &eventsv1.CreateEventResult{
+			Event:   codec.EventToProto(event),
+			Created: created,
+		})
+	}
+	return resp, nil
+}
+
+func (s *EventServer) GetEvent(ctx context.Context, req *eventsv1.GetEventRequest) (*eventsv1.GetEventResponse, error) {
+	if req.GetEventId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "event_id is required")
+	}
+	event, err := s.events.GetEvent(ctx, req.GetEventId())
+	if errors.Is(err, storage.ErrEventNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &eventsv1.GetEventResponse{Event: codec.EventToProto(event)}, nil
+}
+
+func (s *EventServer) QueryEvents(req *eventsv1.QueryEventsRequest, stream eventsv1.EventService_QueryEventsServer) error {
+	filter := fromProtoFilter(req.GetFilter())
+	if err := filter.Validate(); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	// Each event is sent as it is read, so the result is never held in
+	// memory as a whole.
+	err := s.events.ScanEvents(stream.Context(), filter, func(event *models.Event) error {
+		return stream.Send(&eventsv1.QueryEventsResponse{Event: codec.EventToProto(event)})
+	})
+	if ctxErr := stream.Context().Err(); err != nil && ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	return err
+}
+
+func (s *EventServer) GetAnalytics(ctx context.Context, req *eventsv1.GetAnalyticsRequest) (*eventsv1.GetAnalyticsResponse, error) {
+	query, err := analyticsQuery(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	analytics, err := s.analytics.GetAnalytics(ctx, query)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	analytics.TimeWindow = req.GetWindow()
+	return &eventsv1.GetAnalyticsResponse{Analytics: toProtoAnalytics(analytics)}, nil
+}
+
+// analyticsQuery applies the same defaults and validation as GET /analytics.
+func analyticsQuery(req *eventsv1.GetAnalyticsRequest) (*models.AnalyticsQuery, error) {
+	var start, end *time.Time
+	if req.GetStart() != nil {
+		t := req.GetStart().AsTime()
+		start = &t
+	}
+	if req.GetEnd() != nil {
+		t := req.GetEnd().AsTime()
+		end = &t
+	}
+	filter, err := models.RangeFilter(req.GetWindow(), start, end)
+	if err != nil {
+		return nil, err
+	}
+	predicates := fromProtoFilter(req.GetFilter())
+	filter.UserID = predicates.UserID
+	filter.EventType = predicates.EventType
+	filter.Properties = predicates.Properties
+
+	query := &models.AnalyticsQuery{
+		Filter:           filter,
+		ExactUniqueUsers: req.GetExactUniqueUsers(),
+		Granularity:      models.Granularity(req.GetGranularity()),
+		GroupBy:          req.GetGroupBy(),
+		Top:              int(req.GetTop()),
+		Sessions:         req.GetIncludeSessions(),
+		SessionGap:       req.GetSessionGap().AsDuration(),
+		Stats:            req.GetStats(),
+		HistogramBuckets: int(req.GetHistogramBuckets()),
+		Compare:          models.CompareMode(req.GetCompare()),
+	}
+	if query.Location, err = time.LoadLocation(req.GetTz()); err != nil {
+		return nil, errors.New("invalid tz")
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// StreamEvents works like /ws/events: every event stored, whichever API
+// it arrived through, is sent to every open stream, while rejections only
+// go back to their sender.
+func (s *EventServer) StreamEvents(stream eventsv1.EventService_StreamEventsServer) error {
+	events, unsubscribe := s.stream.Subscribe()
+	defer unsubscribe()
+	rejected := make(chan *eventsv1.StreamEventsResponse, rejectedBuffer)
+
+	received := make(chan error, 1)
+	go func() {
+		received <- s.receive(stream, rejected)
+	}()
+	for {
+		select {
+		case err := <-received:
+			// Deliver what the client's own last events produced before
+			// ending the stream.
+			for {
+				select {
+				case event := <-events:
+					if err := stream.Send(eventResponse(event)); err != nil {
+						return err
+					}
+				case resp := <-rejected:
+					if err := stream.Send(resp); err != nil {
+						return err
+					}
+				default:
+					return err
+				}
+			}
+		case event := <-events:
+			if err := stream.Send(eventResponse(event)); err != nil {
+				return err
+			}
+		case resp := <-rejected:
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func eventResponse(event *models.Event) *eventsv1.StreamEventsResponse {
+	return &eventsv1.StreamEventsResponse{Result: &eventsv1.StreamEventsResponse_Event{Event: codec.EventToProto(event)}}
+}
+
+// receive ingests the events sent on stream until the client closes it,
+// reporting those rejected back on responses.
+func (s *EventServer) receive(stream eventsv1.EventService_StreamEventsServer, responses chan *eventsv1.StreamEventsResponse) error {
+	ctx := stream.Context()
+	country := countryFromMetadata(ctx)
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, _, err = s.events.IngestEvent(ctx, &models.CreateEventRequest{Event: *codec.EventFromProto(req.GetEvent())}, country)
+		var invalid *services.ValidationError
+		switch {
+		case errors.As(err, &invalid):
+			rejected := &eventsv1.StreamEventsResponse{Result: &eventsv1.StreamEventsResponse_Rejected{
+				Rejected: &eventsv1.RejectedEvent{EventId: req.GetEvent().GetEventId(), Error: err.Error()},
+			}}
+			select {
+			case responses <- rejected:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		case err != nil:
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+}
+
+// countryMetadata are the gRPC equivalents of the country headers set in
+// front of the HTTP API, in order of preference.
+var countryMetadata = []string{"cf-ipcountry", "x-country-code"}
+
+func countryFromMetadata(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range countryMetadata {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return strings.ToUpper(values[0])
+		}
+	}
+	return ""
+}
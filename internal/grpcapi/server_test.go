@@ -0,0 +1,242 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	eventsv1 "github.com/dnakolan/event-processing-service/api/events/v1"
+	"github.com/dnakolan/event-processing-service/internal/codec"
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestClient(t *testing.T) eventsv1.EventServiceClient {
+	t.Helper()
+	return newTestClientWithStorage(t, storage.NewEventStorage())
+}
+
+func newTestClientWithStorage(t *testing.T, store storage.EventStorage) eventsv1.EventServiceClient {
+	t.Helper()
+	stream := services.NewEventStream()
+	return serveTestClient(t, NewEventServer(
+		services.NewEventsService(store, stream),
+		services.NewAnalyticsService(store, config.AnalyticsConfig{ExactUniqueLimit: 1000, BaseCurrency: "USD"}),
+		stream,
+	))
+}
+
+func serveTestClient(t *testing.T, eventServer *EventServer) eventsv1.EventServiceClient {
+	t.Helper()
+	server := grpc.NewServer(ServerOptions()...)
+	eventsv1.RegisterEventServiceServer(server, eventServer)
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return eventsv1.NewEventServiceClient(conn)
+}
+
+func pageView(id, userID, page string) *eventsv1.Event {
+	return &eventsv1.Event{
+		EventId:    id,
+		UserId:     userID,
+		EventType:  eventsv1.EventType_EVENT_TYPE_PAGE_VIEW,
+		Timestamp:  timestamppb.Now(),
+		Properties: &eventsv1.EventProperties{Page: page},
+	}
+}
+
+func TestEventServer_CreateAndQuery(t *testing.T) {
+	client := newTestClient(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "cf-ipcountry", "de")
+
+	created, err := client.CreateEvents(ctx, &eventsv1.CreateEventsRequest{Events: []*eventsv1.Event{
+		pageView("1", "alice", "/home"),
+		pageView("2", "bob", ""),
+		pageView("3", "bob", "/pricing"),
+		pageView("1", "alice", "/home"),
+	}})
+	require.NoError(t, err)
+	require.Len(t, created.Results, 4)
+	assert.True(t, created.Results[0].Created)
+	assert.Equal(t, "DE", created.Results[0].Event.Properties.Country)
+	assert.Equal(t, "page is required for page_view events", created.Results[1].Error)
+	assert.True(t, created.Results[2].Created)
+	assert.False(t, created.Results[3].Created, "duplicate")
+	assert.Equal(t, "1", created.Results[3].Event.EventId)
+
+	got, err := client.GetEvent(ctx, &eventsv1.GetEventRequest{EventId: "3"})
+	require.NoError(t, err)
+	assert.Equal(t, "/pricing", got.Event.Properties.Page)
+	_, err = client.GetEvent(ctx, &eventsv1.GetEventRequest{EventId: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	userID := "bob"
+	stream, err := client.QueryEvents(ctx, &eventsv1.QueryEventsRequest{Filter: &eventsv1.EventFilter{UserId: &userID}})
+	require.NoError(t, err)
+	var ids []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, resp.Event.EventId)
+	}
+	assert.Equal(t, []string{"3"}, ids)
+
+	analytics, err := client.GetAnalytics(ctx, &eventsv1.GetAnalyticsRequest{
+		Window: "1h",
+		Filter: &eventsv1.EventFilter{Properties: []*eventsv1.PropertyPredicate{{Field: "page", Value: "/home"}}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), analytics.Analytics.TotalEvents)
+	assert.Equal(t, map[string]int64{"page_view": 1}, analytics.Analytics.EventsByType)
+	assert.Equal(t, "1h", analytics.Analytics.TimeWindow)
+
+	_, err = client.GetAnalytics(ctx, &eventsv1.GetAnalyticsRequest{Window: "1h", GroupBy: "nope"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestEventServer_StreamEvents(t *testing.T) {
+	client := newTestClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener, err := client.StreamEvents(ctx)
+	require.NoError(t, err)
+	sender, err := client.StreamEvents(ctx)
+	require.NoError(t, err)
+	// The listener only receives broadcasts once it is registered, which
+	// its first, rejected, event proves.
+	require.NoError(t, listener.Send(&eventsv1.StreamEventsRequest{Event: &eventsv1.Event{EventId: "bad"}}))
+	resp, err := listener.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "bad", resp.GetRejected().GetEventId())
+
+	require.NoError(t, sender.Send(&eventsv1.StreamEventsRequest{Event: pageView("1", "alice", "/home")}))
+	require.NoError(t, sender.CloseSend())
+
+	resp, err = sender.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "1", resp.GetEvent().GetEventId(), "echoed to the sender")
+	_, err = sender.Recv()
+	assert.Equal(t, io.EOF, err)
+
+	resp, err = listener.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "1", resp.GetEvent().GetEventId(), "broadcast to other streams")
+}
+
+func TestEventServer_StreamEventsFromOtherAPIs(t *testing.T) {
+	store := storage.NewEventStorage()
+	stream := services.NewEventStream()
+	events := services.NewEventsService(store, stream)
+	client := serveTestClient(t, NewEventServer(events, services.NewAnalyticsService(store, config.AnalyticsConfig{}), stream))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listener, err := client.StreamEvents(ctx)
+	require.NoError(t, err)
+	require.NoError(t, listener.Send(&eventsv1.StreamEventsRequest{Event: &eventsv1.Event{EventId: "bad"}}))
+	resp, err := listener.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "bad", resp.GetRejected().GetEventId())
+
+	// As an event sent over HTTP, WebSocket or Kafka would be.
+	req := &models.CreateEventRequest{Event: *codec.EventFromProto(pageView("1", "alice", "/home"))}
+	_, _, err = events.IngestEvent(ctx, req, "")
+	require.NoError(t, err)
+
+	resp, err = listener.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "1", resp.GetEvent().GetEventId())
+}
+
+func TestEventServer_QueryEventsWithoutTimestamp(t *testing.T) {
+	store := storage.NewEventStorage()
+	ctx := context.Background()
+	timestamp := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Save(ctx, &models.Event{EventID: "1", UserID: "alice", EventType: models.EventTypeSignup, Timestamp: &timestamp}))
+	require.NoError(t, store.Save(ctx, &models.Event{EventID: "2", UserID: "alice", EventType: models.EventTypeSignup}))
+	client := newTestClientWithStorage(t, store)
+
+	stream, err := client.QueryEvents(ctx, &eventsv1.QueryEventsRequest{})
+	require.NoError(t, err)
+	var ids []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, resp.Event.EventId)
+	}
+	assert.Equal(t, []string{"2", "1"}, ids)
+}
+
+func TestEventServer_QueryEventsStreamsPages(t *testing.T) {
+	store := storage.NewEventStorage()
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	// More events than storage reads at once, across two users.
+	var want []string
+	for _, user := range []string{"alice", "bob"} {
+		for i := range 1500 {
+			id := fmt.Sprintf("%s-%04d", user, i)
+			timestamp := t0.Add(time.Duration(i) * time.Second)
+			require.NoError(t, store.Save(ctx, &models.Event{EventID: id, UserID: user, EventType: models.EventTypeSignup, Timestamp: &timestamp}))
+			want = append(want, id)
+		}
+	}
+	client := newTestClientWithStorage(t, store)
+
+	stream, err := client.QueryEvents(ctx, &eventsv1.QueryEventsRequest{})
+	require.NoError(t, err)
+	var ids []string
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, resp.Event.EventId)
+	}
+	assert.Equal(t, want, ids)
+}
+
+func TestServerOptions_RecoverPanics(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/test/Unary"}
+	_, err := recoverUnary(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		panic("boom")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	err = recoverStream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, func(any, grpc.ServerStream) error {
+		panic("boom")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
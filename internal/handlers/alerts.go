@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/notifiers"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+type AlertsHandler struct {
+	service  services.AlertsService
+	stream   *notifiers.StreamNotifier
+	upgrader *websocket.Upgrader
+}
+
+func NewAlertsHandler(service services.AlertsService, stream *notifiers.StreamNotifier) *AlertsHandler {
+	return &AlertsHandler{
+		service:  service,
+		stream:   stream,
+		upgrader: &websocket.Upgrader{EnableCompression: true},
+	}
+}
+
+func (h *AlertsHandler) ListAlertsHandler(c *gin.Context) {
+	status := models.AlertStatus(c.Query("status"))
+	switch status {
+	case "", models.AlertStatusActive, models.AlertStatusResolved:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be active or resolved"})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, h.service.ListAlerts(c.Request.Context(), status))
+}
+
+func (h *AlertsHandler) ListAlertRulesHandler(c *gin.Context) {
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, h.service.ListRules(c.Request.Context()))
+}
+
+func (h *AlertsHandler) StreamAlertsWebSocketHandler(c *gin.Context) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer conn.Close()
+
+	alerts, unsubscribe := h.stream.Subscribe()
+	// Reading is how we notice that the client has gone away.
+	go func() {
+		defer unsubscribe()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for alert := range alerts {
+		if err := conn.WriteJSON(alert); err != nil {
+			slog.Error("failed to push alert", "error", err.Error())
+			unsubscribe()
+			return
+		}
+	}
+}
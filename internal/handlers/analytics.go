@@ -1,35 +1,103 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dnakolan/event-processing-service/internal/models"
 	"github.com/dnakolan/event-processing-service/internal/services"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
 type AnalyticsHandler struct {
-	service services.AnalyticsService
+	service  services.AnalyticsService
+	upgrader *websocket.Upgrader
 }
 
 func NewAnalyticsHandler(service services.AnalyticsService) *AnalyticsHandler {
 	return &AnalyticsHandler{
-		service: service,
+		service:  service,
+		upgrader: &websocket.Upgrader{EnableCompression: true},
 	}
 }
 
 func (h *AnalyticsHandler) GetAnalyticsHandler(c *gin.Context) {
 	window := c.Query("window")
 
-	filter, err := buildFilterFromWindow(&window)
+	filter, err := buildFilterFromRange(window, c.Query("start"), c.Query("end"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	analytics, err := h.service.GetAnalytics(c.Request.Context(), filter)
+	if err := applyFilterParams(filter, c.Request.URL.Query()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := &models.AnalyticsQuery{
+		Filter:      filter,
+		Granularity: models.Granularity(c.Query("granularity")),
+		GroupBy:     c.Query("group_by"),
+		Compare:     models.CompareMode(c.Query("compare")),
+	}
+	if top := c.Query("top"); top != "" {
+		if query.Top, err = strconv.Atoi(top); err != nil || query.Top <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "top must be between 1 and 100"})
+			return
+		}
+	}
+	if exact := c.Query("exact"); exact != "" {
+		if query.ExactUniqueUsers, err = strconv.ParseBool(exact); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "exact must be a boolean"})
+			return
+		}
+	}
+	if query.Location, err = time.LoadLocation(c.Query("tz")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tz"})
+		return
+	}
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		switch include {
+		case "":
+		case "sessions":
+			query.Sessions = true
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include " + include})
+			return
+		}
+	}
+	if gap := c.Query("session_gap"); gap != "" {
+		if query.SessionGap, err = time.ParseDuration(gap); err != nil || query.SessionGap <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "session_gap must be a positive duration"})
+			return
+		}
+	}
+	for _, path := range strings.Split(c.Query("stats"), ",") {
+		if path != "" {
+			query.Stats = append(query.Stats, path)
+		}
+	}
+	if buckets := c.Query("histogram_buckets"); buckets != "" {
+		if query.HistogramBuckets, err = strconv.Atoi(buckets); err != nil || query.HistogramBuckets <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "histogram_buckets must be between 1 and 100"})
+			return
+		}
+	}
+	if err := query.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	analytics, err := h.service.GetAnalytics(c.Request.Context(), query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -40,20 +108,237 @@ func (h *AnalyticsHandler) GetAnalyticsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, analytics)
 }
 
-func buildFilterFromWindow(window *string) (*models.EventFilter, error) {
+func (h *AnalyticsHandler) CreateFunnelHandler(c *gin.Context) {
+	var req models.FunnelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	funnel, err := h.service.GetFunnel(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, funnel)
+}
+
+func (h *AnalyticsHandler) GetRetentionHandler(c *gin.Context) {
+	window := c.Query("window")
+
+	filter, err := buildFilterFromRange(window, c.Query("start"), c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := &models.RetentionQuery{
+		Cohort:      models.Granularity(c.DefaultQuery("cohort", string(models.GranularityDay))),
+		Start:       *filter.StartTimestamp,
+		End:         *filter.EndTimestamp,
+		CohortEvent: models.EventType(c.Query("cohort_event")),
+		ReturnEvent: models.EventType(c.Query("return_event")),
+		Periods:     models.DefaultRetentionDays,
+	}
+	if query.Cohort == models.GranularityWeek {
+		query.Periods = models.DefaultRetentionWeeks
+	}
+	if periods := c.Query("periods"); periods != "" {
+		if query.Periods, err = strconv.Atoi(periods); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "periods must be an integer"})
+			return
+		}
+	}
+	if query.Location, err = time.LoadLocation(c.Query("tz")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tz"})
+		return
+	}
+	if err := query.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	retention, err := h.service.GetRetention(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, retention)
+}
+
+func (h *AnalyticsHandler) GetTopHandler(c *gin.Context) {
+	filter, err := buildFilterFromRange(c.Query("window"), c.Query("start"), c.Query("end"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := &models.TopQuery{
+		Dimension: models.TopDimension(c.Param("dimension")),
+		Start:     *filter.StartTimestamp,
+		End:       *filter.EndTimestamp,
+		Limit:     models.DefaultTopLimit,
+		Mode:      models.TopMode(c.DefaultQuery("mode", string(models.TopModeAuto))),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+	}
+	if err := query.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	top, err := h.service.GetTop(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, top)
+}
+
+func (h *AnalyticsHandler) StreamAnalyticsWebSocketHandler(c *gin.Context) {
+	window := c.Query("window")
+
+	opts, err := buildStreamOptions(&window, c.Query("interval"), c.Query("every"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	updates, err := h.service.StreamAnalytics(ctx, *opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer conn.Close()
+
+	// The client never sends anything meaningful, but reading is how we
+	// notice that it has gone away.
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for analytics := range updates {
+		analytics.TimeWindow = window
+		if err := conn.WriteJSON(analytics); err != nil {
+			slog.Error("failed to push analytics", "error", err.Error())
+			return
+		}
+	}
+}
+
+// buildFilterFromRange accepts either a window relative to end (which
+// defaults to now) or explicit RFC3339 start and end bounds.
+func buildFilterFromRange(window, start, end string) (*models.EventFilter, error) {
+	var startTime, endTime *time.Time
+	if end != "" {
+		t, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return nil, errors.New("end must be an RFC3339 timestamp")
+		}
+		endTime = &t
+	}
+	if start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, errors.New("start must be an RFC3339 timestamp")
+		}
+		startTime = &t
+	}
+	return models.RangeFilter(window, startTime, endTime)
+}
+
+// applyFilterParams adds the user_id, event_type and property predicates
+// from the query string to filter. Properties are matched for equality with
+// properties.page=/home, or with another op as properties.amount[gte]=10.
+func applyFilterParams(filter *models.EventFilter, params url.Values) error {
+	if userID, ok := params["user_id"]; ok {
+		filter.UserID = &userID[0]
+	}
+	if eventType, ok := params["event_type"]; ok {
+		t := models.EventType(eventType[0])
+		filter.EventType = &t
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if strings.HasPrefix(key, "properties.") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := strings.TrimPrefix(key, "properties.")
+		op := models.PredicateOpEq
+		if i := strings.IndexByte(field, '['); i >= 0 && strings.HasSuffix(field, "]") {
+			op = models.PredicateOp(field[i+1 : len(field)-1])
+			field = field[:i]
+		}
+		for _, value := range params[key] {
+			filter.Properties = append(filter.Properties, models.PropertyPredicate{
+				Field: field,
+				Op:    op,
+				Value: value,
+			})
+		}
+	}
+	return filter.Validate()
+}
+
+func buildStreamOptions(window *string, interval string, every string) (*services.StreamOptions, error) {
 	if window == nil || *window == "" {
 		return nil, errors.New("window is required")
 	}
 
-	timeWindow, err := time.ParseDuration(*window)
-	if err != nil {
+	opts := &services.StreamOptions{}
+	var err error
+	if opts.Window, err = models.ParseWindow(*window); err != nil {
 		return nil, err
 	}
-
-	start := time.Now().Add(-timeWindow)
-	end := time.Now()
-	return &models.EventFilter{
-		StartTimestamp: &start,
-		EndTimestamp:   &end,
-	}, nil
+	if interval != "" {
+		if opts.Interval, err = time.ParseDuration(interval); err != nil {
+			return nil, err
+		}
+		if opts.Interval <= 0 {
+			return nil, errors.New("interval must be greater than 0")
+		}
+	}
+	if every != "" {
+		if opts.EveryN, err = strconv.Atoi(every); err != nil {
+			return nil, errors.New("every must be an integer")
+		}
+		if opts.EveryN <= 0 {
+			return nil, errors.New("every must be greater than 0")
+		}
+	}
+	return opts, nil
 }
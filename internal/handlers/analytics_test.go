@@ -0,0 +1,401 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/aggregation"
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+func TestGetAnalyticsHandler(t *testing.T) {
+	store := storage.NewEventStorage()
+	handler := NewAnalyticsHandler(services.NewAnalyticsService(store, config.AnalyticsConfig{}))
+
+	// 03:30Z on the 2nd is still the 1st in New York.
+	for i, ts := range []string{"2025-06-01T12:00:00Z", "2025-06-02T03:30:00Z", "2025-06-03T12:00:00Z"} {
+		timestamp, _ := time.Parse(time.RFC3339, ts)
+		store.Save(context.Background(), &models.Event{
+			EventID:   string(rune('a' + i)),
+			UserID:    "123",
+			EventType: models.EventTypeClick,
+			Timestamp: &timestamp,
+		})
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedSeries []int
+	}{
+		{
+			name:           "daily series in new york",
+			query:          "start=2025-06-01T04:00:00Z&end=2025-06-04T03:59:59Z&granularity=day&tz=America/New_York",
+			expectedStatus: http.StatusOK,
+			expectedSeries: []int{2, 0, 1},
+		},
+		{
+			name:           "daily series in utc",
+			query:          "start=2025-06-01T00:00:00Z&end=2025-06-03T23:59:59Z&granularity=day",
+			expectedStatus: http.StatusOK,
+			expectedSeries: []int{1, 1, 1},
+		},
+		{
+			name:           "window and start",
+			query:          "window=7d&start=2025-06-01T00:00:00Z",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid granularity",
+			query:          "window=7d&granularity=fortnight",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many buckets",
+			query:          "window=52w&granularity=minute",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/analytics?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.GetAnalyticsHandler(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedSeries == nil {
+				return
+			}
+			var analytics models.Analytics
+			assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &analytics))
+			counts := make([]int, len(analytics.Series))
+			for i, bucket := range analytics.Series {
+				counts[i] = bucket.Count
+			}
+			assert.Equal(t, tt.expectedSeries, counts)
+		})
+	}
+}
+
+func TestGetAnalyticsHandler_GroupBy(t *testing.T) {
+	store := storage.NewEventStorage()
+	handler := NewAnalyticsHandler(services.NewAnalyticsService(store, config.AnalyticsConfig{}))
+
+	now := time.Now()
+	pages := []string{"/home", "/home", "/home", "/pricing", "/pricing", "/about", "/blog"}
+	for i, page := range pages {
+		store.Save(context.Background(), &models.Event{
+			EventID:    string(rune('a' + i)),
+			UserID:     string(rune('a' + i%3)),
+			EventType:  models.EventTypePageView,
+			Timestamp:  &now,
+			Properties: models.EventProperties{Page: page, Country: "DE"},
+		})
+	}
+	store.Save(context.Background(), &models.Event{
+		EventID:    "purchase",
+		UserID:     "a",
+		EventType:  models.EventTypePurchase,
+		Timestamp:  &now,
+		Properties: models.EventProperties{Amount: 25, ProductID: "xyz"},
+	})
+
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedTotal  int
+		expectedGroups map[string]int
+		expectedOther  int
+	}{
+		{
+			name:           "top pages with other",
+			query:          "window=1h&event_type=page_view&group_by=properties.page&top=2",
+			expectedStatus: http.StatusOK,
+			expectedTotal:  7,
+			expectedGroups: map[string]int{"/home": 3, "/pricing": 2},
+			expectedOther:  2,
+		},
+		{
+			name:           "derived country",
+			query:          "window=1h&group_by=country",
+			expectedStatus: http.StatusOK,
+			expectedTotal:  8,
+			expectedGroups: map[string]int{"DE": 7, models.GroupKeyUnknown: 1},
+		},
+		{
+			name:           "property predicates",
+			query:          "window=1h&properties.amount[gte]=20&properties.product_id=xyz",
+			expectedStatus: http.StatusOK,
+			expectedTotal:  1,
+		},
+		{
+			name:           "prefix predicate grouped by user",
+			query:          "window=1h&properties.page[prefix]=/p&group_by=user_id",
+			expectedStatus: http.StatusOK,
+			expectedTotal:  2,
+			expectedGroups: map[string]int{"a": 1, "b": 1},
+		},
+		{
+			name:           "unknown property",
+			query:          "window=1h&properties.color=red",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "non-numeric comparison",
+			query:          "window=1h&properties.amount[gt]=lots",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid group_by",
+			query:          "window=1h&group_by=properties.color",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/analytics?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.GetAnalyticsHandler(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var analytics models.Analytics
+			assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &analytics))
+			assert.Equal(t, tt.expectedTotal, analytics.TotalEvents)
+			if tt.expectedGroups == nil {
+				return
+			}
+			groups := make(map[string]int)
+			for _, group := range analytics.Groups {
+				groups[group.Key] = group.TotalEvents
+			}
+			assert.Equal(t, tt.expectedGroups, groups)
+			if tt.expectedOther > 0 {
+				assert.Equal(t, tt.expectedOther, analytics.Other.TotalEvents)
+			} else {
+				assert.Equal(t, (*models.AnalyticsGroup)(nil), analytics.Other)
+			}
+		})
+	}
+}
+
+func TestGetAnalyticsHandler_Stats(t *testing.T) {
+	store := storage.NewEventStorage()
+	handler := NewAnalyticsHandler(services.NewAnalyticsService(store, config.AnalyticsConfig{}))
+
+	now := time.Now()
+	for i, amount := range []float64{10, 20, 30, 45, 100} {
+		store.Save(context.Background(), &models.Event{
+			EventID:    string(rune('a' + i)),
+			UserID:     string(rune('a' + i%2)),
+			EventType:  models.EventTypePurchase,
+			Timestamp:  &now,
+			Properties: models.EventProperties{Amount: amount, ProductID: "xyz"},
+		})
+	}
+	store.Save(context.Background(), &models.Event{
+		EventID:    "view",
+		UserID:     "a",
+		EventType:  models.EventTypePageView,
+		Timestamp:  &now,
+		Properties: models.EventProperties{Page: "/home"},
+	})
+
+	tests := []struct {
+		name              string
+		query             string
+		expectedStatus    int
+		expectedCount     int
+		expectedMax       float64
+		expectedP50       float64
+		expectedHistogram []int
+		approximate       bool
+	}{
+		{
+			name:              "from rollups",
+			query:             "window=1h&stats=properties.amount&histogram_buckets=3",
+			expectedStatus:    http.StatusOK,
+			expectedCount:     5,
+			expectedMax:       100,
+			expectedP50:       30,
+			expectedHistogram: []int{3, 1, 1},
+			approximate:       true,
+		},
+		{
+			name:              "exact when filtered",
+			query:             "window=1h&user_id=a&stats=properties.amount&histogram_buckets=2",
+			expectedStatus:    http.StatusOK,
+			expectedCount:     3,
+			expectedMax:       100,
+			expectedP50:       30,
+			expectedHistogram: []int{2, 1},
+		},
+		{
+			name:           "non-numeric property",
+			query:          "window=1h&stats=properties.page",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "too many buckets",
+			query:          "window=1h&stats=properties.amount&histogram_buckets=500",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/analytics?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.GetAnalyticsHandler(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var analytics models.Analytics
+			assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &analytics))
+			assert.Equal(t, 1, len(analytics.Stats))
+			stats := analytics.Stats[0]
+			assert.Equal(t, "properties.amount", stats.Property)
+			assert.Equal(t, tt.expectedCount, stats.Count)
+			assert.Equal(t, tt.expectedMax, stats.Max)
+			assert.Equal(t, tt.approximate, stats.Approximate)
+			assert.Equal(t, true, math.Abs(stats.P50-tt.expectedP50) <= tt.expectedP50*aggregation.DDSketchRelativeError)
+			counts := make([]int, len(stats.Histogram))
+			for i, bucket := range stats.Histogram {
+				counts[i] = bucket.Count
+			}
+			assert.Equal(t, tt.expectedHistogram, counts)
+		})
+	}
+}
+
+func TestGetAnalyticsHandler_Compare(t *testing.T) {
+	store := storage.NewEventStorage()
+	handler := NewAnalyticsHandler(services.NewAnalyticsService(store, config.AnalyticsConfig{}))
+
+	save := func(id, userID string, eventType models.EventType, ts string) {
+		timestamp, _ := time.Parse(time.RFC3339, ts)
+		store.Save(context.Background(), &models.Event{
+			EventID:   id,
+			UserID:    userID,
+			EventType: eventType,
+			Timestamp: &timestamp,
+		})
+	}
+	// The week before.
+	save("a", "1", models.EventTypeClick, "2025-05-26T10:00:00Z")
+	// The day before.
+	save("b", "1", models.EventTypeClick, "2025-06-01T10:00:00Z")
+	save("c", "2", models.EventTypeSignup, "2025-06-01T11:00:00Z")
+	// Exactly on the boundary, which belongs to the current day only.
+	save("d", "1", models.EventTypeClick, "2025-06-02T00:00:00Z")
+	save("e", "2", models.EventTypeClick, "2025-06-02T10:00:00Z")
+	save("f", "3", models.EventTypeClick, "2025-06-02T11:00:00Z")
+
+	tests := []struct {
+		name             string
+		query            string
+		expectedStatus   int
+		expectedPrevious int
+		expectedDelta    int
+		expectedPercent  *float64
+		expectedByType   map[models.EventType]int
+	}{
+		{
+			name:             "previous period",
+			query:            "start=2025-06-02T00:00:00Z&end=2025-06-02T23:59:59Z&compare=previous_period",
+			expectedStatus:   http.StatusOK,
+			expectedPrevious: 2,
+			expectedDelta:    1,
+			expectedPercent:  float64Ptr(50),
+			expectedByType:   map[models.EventType]int{models.EventTypeClick: 2, models.EventTypeSignup: -1},
+		},
+		{
+			name:             "previous week",
+			query:            "start=2025-06-02T00:00:00Z&end=2025-06-02T23:59:59Z&compare=previous_week&event_type=click",
+			expectedStatus:   http.StatusOK,
+			expectedPrevious: 1,
+			expectedDelta:    2,
+			expectedPercent:  float64Ptr(200),
+			expectedByType:   map[models.EventType]int{models.EventTypeClick: 2},
+		},
+		{
+			name:             "nothing to compare with",
+			query:            "start=2025-05-20T00:00:00Z&end=2025-05-20T23:59:59Z&compare=previous_period",
+			expectedStatus:   http.StatusOK,
+			expectedPrevious: 0,
+			expectedDelta:    0,
+			expectedByType:   map[models.EventType]int{},
+		},
+		{
+			name:           "invalid compare",
+			query:          "window=1d&compare=last_year",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/analytics?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			gin.SetMode(gin.TestMode)
+			c, _ := gin.CreateTestContext(w)
+			c.Request = req
+
+			handler.GetAnalyticsHandler(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			var analytics models.Analytics
+			assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &analytics))
+			comparison := analytics.Comparison
+			assert.Equal(t, tt.expectedPrevious, comparison.TotalEvents.Previous)
+			assert.Equal(t, tt.expectedDelta, comparison.TotalEvents.Delta)
+			assert.Equal(t, tt.expectedPercent, comparison.TotalEvents.PercentChange)
+			byType := make(map[models.EventType]int)
+			for eventType, delta := range comparison.EventsByType {
+				byType[eventType] = delta.Delta
+			}
+			assert.Equal(t, tt.expectedByType, byType)
+		})
+	}
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DecompressRequest decodes request bodies sent with a Content-Encoding of
+// gzip, deflate or zstd. A compressed body may expand to at most limit bytes,
+// so a small zip bomb can't expand without bound; handlers find out it went
+// over from the error reading the body.
+func DecompressRequest(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := strings.ToLower(strings.TrimSpace(c.GetHeader("Content-Encoding")))
+		if encoding == "" || encoding == "identity" {
+			c.Next()
+			return
+		}
+
+		decoder, err := newDecoder(encoding, c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+			return
+		}
+		if decoder == nil {
+			// The stream's header couldn't be read, so there is no body to
+			// decode.
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + encoding + " body"})
+			return
+		}
+		defer decoder.Close()
+
+		c.Request.Body = &decompressedBody{
+			reader:  http.MaxBytesReader(c.Writer, decoder, limit),
+			decoder: decoder,
+		}
+		c.Request.Header.Del("Content-Encoding")
+		c.Request.ContentLength = -1
+		c.Next()
+	}
+}
+
+// newDecoder returns an error for an encoding it doesn't support, and a nil
+// decoder for a body that doesn't start as that encoding should.
+func newDecoder(encoding string, body io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		decoder, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, nil
+		}
+		return decoder, nil
+	case "deflate":
+		decoder, err := zlib.NewReader(body)
+		if err != nil {
+			return nil, nil
+		}
+		return decoder, nil
+	case "zstd":
+		decoder, err := zstd.NewReader(body, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, nil
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", encoding)
+	}
+}
+
+// decompressedBody remembers whether the body went over its limit, for
+// handlers that don't fail on a body read error.
+type decompressedBody struct {
+	reader   io.Reader
+	decoder  io.Closer
+	tooLarge *http.MaxBytesError
+}
+
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	n, err := b.reader.Read(p)
+	if err != nil && b.tooLarge == nil {
+		errors.As(err, &b.tooLarge)
+	}
+	return n, err
+}
+
+func (b *decompressedBody) Close() error {
+	return b.decoder.Close()
+}
+
+// bodyTooLarge reports the limit a decompressed request body went over.
+func bodyTooLarge(c *gin.Context) (*http.MaxBytesError, bool) {
+	body, ok := c.Request.Body.(*decompressedBody)
+	if !ok || body.tooLarge == nil {
+		return nil, false
+	}
+	return body.tooLarge, true
+}
+
+// writeBodyError reports a failure to read the request body.
+func writeBodyError(c *gin.Context, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLargeMessage(tooLarge)})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+func tooLargeMessage(err *http.MaxBytesError) string {
+	return "decompressed body is larger than " + strconv.FormatInt(err.Limit, 10) + " bytes"
+}
+
+// responseEncodings are the encodings CompressResponse can use, in order of
+// preference when the client accepts several equally.
+var responseEncodings = []string{"zstd", "gzip", "deflate"}
+
+// CompressResponse compresses the response with the encoding the client
+// prefers from its Accept-Encoding. Responses are encoded as they are
+// written, so streamed ones stay streamed.
+func CompressResponse() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		w := &compressedWriter{ResponseWriter: c.Writer, encoding: encoding}
+		c.Writer = w
+		c.Next()
+		if err := w.Close(); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
+// negotiateEncoding picks the accepted encoding with the highest q value,
+// or none when identity is preferred or nothing supported is accepted.
+func negotiateEncoding(acceptEncoding string) string {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		qualities[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range responseEncodings {
+		q, ok := qualities[encoding]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	if identity, ok := qualities["identity"]; ok && identity > bestQ {
+		return ""
+	}
+	return best
+}
+
+// compressedWriter starts encoding on the first write, so a response with
+// no body, or one already encoded, goes out as it is.
+type compressedWriter struct {
+	gin.ResponseWriter
+	encoding string
+	encoder  io.WriteCloser
+	bypass   bool
+}
+
+func (w *compressedWriter) Write(p []byte) (int, error) {
+	if w.encoder == nil && !w.bypass {
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+	}
+	if w.bypass {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.encoder.Write(p)
+}
+
+func (w *compressedWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressedWriter) start() error {
+	header := w.Header()
+	if w.ResponseWriter.Written() || header.Get("Content-Encoding") != "" {
+		w.bypass = true
+		return nil
+	}
+	switch w.encoding {
+	case "zstd":
+		encoder, err := zstd.NewWriter(w.ResponseWriter, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return err
+		}
+		w.encoder = encoder
+	case "gzip":
+		w.encoder = gzip.NewWriter(w.ResponseWriter)
+	default:
+		w.encoder = zlib.NewWriter(w.ResponseWriter)
+	}
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	return nil
+}
+
+// Flush pushes out what has been encoded so far, for streamed responses.
+func (w *compressedWriter) Flush() {
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressedWriter) Close() error {
+	if w.encoder == nil {
+		return nil
+	}
+	return w.encoder.Close()
+}
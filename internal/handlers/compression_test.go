@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/gorilla/websocket"
+	"github.com/klauspost/compress/zstd"
+)
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(data)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, w.Close())
+	return buf.Bytes()
+}
+
+func TestDecompressRequest(t *testing.T) {
+	store := storage.NewEventStorage()
+	stream := services.NewEventStream()
+	events := services.NewEventsService(store, stream)
+	eventsHandler := NewEventsHandler(events, stream)
+	importsHandler := NewImportsHandler(services.NewImportsService(events))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/events", DecompressRequest(1024), eventsHandler.CreateEventsHTTPHandler)
+	router.POST("/events/import", DecompressRequest(300), importsHandler.ImportEventsHandler)
+
+	post := func(path, encoding string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Content-Encoding", encoding)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	event, err := json.Marshal(testEvent("1"))
+	assert.Equal(t, nil, err)
+
+	w := post("/events", "gzip", gzipped(t, event))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	encoder, err := zstd.NewWriter(nil)
+	assert.Equal(t, nil, err)
+	event, err = json.Marshal(testEvent("2"))
+	assert.Equal(t, nil, err)
+	w = post("/events", "zstd", encoder.EncodeAll(event, nil))
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	bomb := gzipped(t, append(event[:len(event)-1], bytes.Repeat([]byte(" "), 1<<20)...))
+	w = post("/events", "gzip", bomb)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = post("/events", "br", event)
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	w = post("/events", "gzip", event)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	lines := strings.Repeat(`{"event_id":"x","user_id":"alice","event_type":"page_view","timestamp":"2024-01-02T03:04:05Z","properties":{"page":"/"}}`+"\n", 10)
+	w = post("/events/import?format=ndjson", "gzip", gzipped(t, []byte(lines)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var resp struct {
+		JobID   string `json:"job_id"`
+		Records int    `json:"records"`
+	}
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotEqual(t, "", resp.JobID)
+	assert.Equal(t, 2, resp.Records)
+}
+
+func TestCompressResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	body := strings.Repeat("compressible ", 100)
+	router.GET("/data", CompressResponse(), func(c *gin.Context) {
+		c.String(http.StatusOK, body)
+	})
+	router.GET("/empty", CompressResponse(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/data", "gzip, deflate")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	reader, err := gzip.NewReader(w.Body)
+	assert.Equal(t, nil, err)
+	decoded, err := io.ReadAll(reader)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, body, string(decoded))
+
+	w = get("/data", "gzip;q=0.5, zstd")
+	assert.Equal(t, "zstd", w.Header().Get("Content-Encoding"))
+	decoder, err := zstd.NewReader(nil)
+	assert.Equal(t, nil, err)
+	decoded, err = decoder.DecodeAll(w.Body.Bytes(), nil)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, body, string(decoded))
+
+	w = get("/data", "")
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, w.Body.String())
+
+	w = get("/empty", "gzip")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"))
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for acceptEncoding, want := range map[string]string{
+		"":                          "",
+		"gzip":                      "gzip",
+		"deflate, gzip":             "gzip",
+		"*":                         "zstd",
+		"zstd;q=0, *;q=0.5":         "gzip",
+		"gzip;q=0.5, identity":      "",
+		"br":                        "",
+		"GZIP;q=0.8, deflate;q=0.9": "deflate",
+	} {
+		assert.Equal(t, want, negotiateEncoding(acceptEncoding))
+	}
+}
+
+func TestWebSocketCompression(t *testing.T) {
+	handler := newTestEventsHandler(storage.NewEventStorage())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws/events", handler.CreateEventsWebSocketHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	dialer := websocket.Dialer{EnableCompression: true}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/events", nil)
+	assert.Equal(t, nil, err)
+	defer conn.Close()
+	assert.Equal(t, true, strings.HasPrefix(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate"))
+
+	event, err := json.Marshal(testEvent("1"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, conn.WriteMessage(websocket.TextMessage, event))
+	_, message, err := conn.ReadMessage()
+	assert.Equal(t, nil, err)
+	var received models.Event
+	assert.Equal(t, nil, json.Unmarshal(message, &received))
+	assert.Equal(t, "1", received.EventID)
+}
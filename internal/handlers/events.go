@@ -2,12 +2,13 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
-	"time"
+	"strings"
 
-	"github.com/dnakolan/event-processing-service/internal/connections"
+	"github.com/dnakolan/event-processing-service/internal/codec"
 	"github.com/dnakolan/event-processing-service/internal/models"
 	"github.com/dnakolan/event-processing-service/internal/services"
 	"github.com/gin-gonic/gin"
@@ -15,16 +16,18 @@ import (
 )
 
 type EventsHandler struct {
-	connections connections.ConnectionManager
-	service     services.EventsService
-	upgrader    *websocket.Upgrader
+	service  services.EventsService
+	stream   *services.EventStream
+	upgrader *websocket.Upgrader
 }
 
-func NewEventsHandler(service services.EventsService) *EventsHandler {
+// NewEventsHandler pushes the events published to stream to every
+// WebSocket client, so it should be one of service's publishers.
+func NewEventsHandler(service services.EventsService, stream *services.EventStream) *EventsHandler {
 	return &EventsHandler{
-		service:     service,
-		upgrader:    &websocket.Upgrader{},
-		connections: connections.NewConnectionManager(),
+		service:  service,
+		stream:   stream,
+		upgrader: &websocket.Upgrader{Subprotocols: codec.Subprotocols(), EnableCompression: true},
 	}
 }
 
@@ -35,8 +38,12 @@ func (h *EventsHandler) CreateEventsWebSocketHandler(c *gin.Context) {
 		return
 	}
 	defer conn.Close()
-	h.connections.AddConnection(conn)
-	defer h.connections.RemoveConnection(conn)
+	country := countryFromHeaders(c.Request.Header)
+	format := codec.ForSubprotocol(conn.Subprotocol())
+
+	events, unsubscribe := h.stream.Subscribe()
+	defer unsubscribe()
+	go pushEvents(conn, format, events)
 
 	for {
 		messageType, message, err := conn.ReadMessage()
@@ -46,11 +53,13 @@ func (h *EventsHandler) CreateEventsWebSocketHandler(c *gin.Context) {
 		}
 		switch messageType {
 		case websocket.TextMessage:
-			// Process your JSON events
-			h.handleEventJSON(c.Request.Context(), message)
+			h.handleEvent(c.Request.Context(), codec.JSON, message, country)
 		case websocket.BinaryMessage:
-			// Maybe reject or handle differently
-			slog.Error("binary data not supported")
+			if !format.Binary() {
+				slog.Error("binary frames need the protobuf or msgpack subprotocol")
+				continue
+			}
+			h.handleEvent(c.Request.Context(), format, message, country)
 		case websocket.CloseMessage:
 			slog.Info("client disconnecting")
 			return
@@ -58,37 +67,93 @@ func (h *EventsHandler) CreateEventsWebSocketHandler(c *gin.Context) {
 	}
 }
 
+// CreateEventsHTTPHandler reads the event as protobuf or MessagePack when
+// the Content-Type says so, and as JSON otherwise. The response is in the
+// format of the request unless Accept asks for another; errors are JSON.
 func (h *EventsHandler) CreateEventsHTTPHandler(c *gin.Context) {
+	format, ok := codec.ForContentType(c.GetHeader("Content-Type"))
+	if !ok {
+		format = codec.JSON
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		writeBodyError(c, err)
+		return
+	}
 	var req models.CreateEventRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := format.Unmarshal(body, &req.Event); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := req.Validate(); err != nil {
+	event, created, err := h.service.IngestEvent(c.Request.Context(), &req, countryFromHeaders(c.Request.Header))
+	var invalid *services.ValidationError
+	if errors.As(err, &invalid) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	now := time.Now()
-	event := req.NewEventFromRequest()
-	event.Timestamp = &now
-
-	if err := h.service.CreateEvent(c.Request.Context(), event); err != nil {
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	format = codec.ForAccept(c.GetHeader("Accept"), format)
+	data, err := format.Marshal(event)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.Data(status, format.ContentType(), data)
+}
 
-	c.Header("Content-Type", "application/json")
-	c.JSON(http.StatusCreated, event)
+// countryHeaders are set by the CDN or load balancer in front of the service
+// with the client's ISO country code, in order of preference.
+var countryHeaders = []string{"CF-IPCountry", "X-Country-Code"}
+
+func countryFromHeaders(header http.Header) string {
+	for _, name := range countryHeaders {
+		if country := header.Get(name); country != "" {
+			return strings.ToUpper(country)
+		}
+	}
+	return ""
 }
 
-func (h *EventsHandler) handleEventJSON(ctx context.Context, message []byte) {
-	var event models.Event
-	if err := json.Unmarshal(message, &event); err != nil {
+// handleEvent ingests one frame like any other event. Frames that are
+// rejected are logged and skipped. Events that are stored reach every
+// client, this one included, through the event stream.
+func (h *EventsHandler) handleEvent(ctx context.Context, format codec.Codec, message []byte, country string) {
+	var req models.CreateEventRequest
+	if err := format.Unmarshal(message, &req.Event); err != nil {
 		slog.Error("failed to unmarshal event", "error", err.Error())
 		return
 	}
-	h.service.CreateEvent(ctx, &event)
-	h.connections.BroadcastEvent(&event)
+	if _, _, err := h.service.IngestEvent(ctx, &req, country); err != nil {
+		slog.Error("failed to ingest event", "event_id", req.EventID, "error", err.Error())
+	}
+}
+
+// pushEvents writes events to conn in the format of its subprotocol until
+// the subscription ends or a write fails, which closes conn.
+func pushEvents(conn *websocket.Conn, format codec.Codec, events <-chan *models.Event) {
+	messageType := websocket.TextMessage
+	if format.Binary() {
+		messageType = websocket.BinaryMessage
+	}
+	for event := range events {
+		message, err := format.Marshal(event)
+		if err != nil {
+			slog.Error("failed to marshal event", "format", format.Name(), "error", err.Error())
+			continue
+		}
+		if err := conn.WriteMessage(messageType, message); err != nil {
+			slog.Error("broadcast failed", "error", err.Error())
+			conn.Close()
+			return
+		}
+	}
 }
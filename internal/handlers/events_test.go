@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/codec"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/gorilla/websocket"
+)
+
+func newTestEventsHandler(store storage.EventStorage) *EventsHandler {
+	stream := services.NewEventStream()
+	return NewEventsHandler(services.NewEventsService(store, stream), stream)
+}
+
+func testEvent(id string) *models.Event {
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &models.Event{
+		EventID:    id,
+		UserID:     "alice",
+		EventType:  models.EventTypePageView,
+		Timestamp:  &timestamp,
+		Properties: models.EventProperties{Page: "/home"},
+	}
+}
+
+func TestCreateEventsHTTPHandler_Formats(t *testing.T) {
+	handler := newTestEventsHandler(storage.NewEventStorage())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/events", handler.CreateEventsHTTPHandler)
+
+	body, err := codec.Protobuf.Marshal(testEvent("1"))
+	assert.Equal(t, nil, err)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/x-protobuf", w.Header().Get("Content-Type"))
+	var event models.Event
+	assert.Equal(t, nil, codec.Protobuf.Unmarshal(w.Body.Bytes(), &event))
+	assert.Equal(t, "1", event.EventID)
+
+	body, err = codec.MessagePack.Marshal(testEvent("2"))
+	assert.Equal(t, nil, err)
+	req = httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/msgpack")
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, true, strings.Contains(w.Body.String(), `"event_id":"2"`))
+
+	req = httptest.NewRequest(http.MethodPost, "/events", strings.NewReader("not protobuf"))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateEventsWebSocketHandler_Subprotocols(t *testing.T) {
+	handler := newTestEventsHandler(storage.NewEventStorage())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws/events", handler.CreateEventsWebSocketHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/events"
+	dialer := websocket.Dialer{Subprotocols: []string{"msgpack"}}
+	conn, _, err := dialer.Dial(url, nil)
+	assert.Equal(t, nil, err)
+	defer conn.Close()
+	assert.Equal(t, "msgpack", conn.Subprotocol())
+
+	message, err := codec.MessagePack.Marshal(testEvent("1"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, conn.WriteMessage(websocket.BinaryMessage, message))
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	messageType, message, err := conn.ReadMessage()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, websocket.BinaryMessage, messageType)
+	var event models.Event
+	assert.Equal(t, nil, codec.MessagePack.Unmarshal(message, &event))
+	assert.Equal(t, "1", event.EventID)
+}
+
+func TestCreateEventsWebSocketHandler_Ingest(t *testing.T) {
+	store := storage.NewEventStorage()
+	handler := newTestEventsHandler(store)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ws/events", handler.CreateEventsWebSocketHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/events"
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"CF-IPCountry": []string{"gb"}})
+	assert.Equal(t, nil, err)
+	defer conn.Close()
+
+	// Rejected frames are skipped and duplicates aren't broadcast again, so
+	// the only events back are 2 and 3.
+	invalid := testEvent("1")
+	invalid.EventType = ""
+	for _, event := range []*models.Event{invalid, testEvent("2"), testEvent("2"), testEvent("3")} {
+		assert.Equal(t, nil, conn.WriteJSON(event))
+	}
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for _, id := range []string{"2", "3"} {
+		var event models.Event
+		assert.Equal(t, nil, conn.ReadJSON(&event))
+		assert.Equal(t, id, event.EventID)
+		assert.Equal(t, "GB", event.Properties.Country)
+	}
+
+	_, err = store.FindById(context.Background(), "1")
+	assert.Equal(t, storage.ErrEventNotFound, err)
+	stored, err := store.FindById(context.Background(), "2")
+	assert.Equal(t, nil, err)
+	assert.NotEqual(t, nil, stored.Timestamp)
+}
+
+func TestCreateEventsWebSocketHandler_BroadcastsHTTPEvents(t *testing.T) {
+	handler := newTestEventsHandler(storage.NewEventStorage())
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/events", handler.CreateEventsHTTPHandler)
+	router.GET("/ws/events", handler.CreateEventsWebSocketHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws/events"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Equal(t, nil, err)
+	defer conn.Close()
+	// The connection is subscribed once its own event comes back.
+	assert.Equal(t, nil, conn.WriteJSON(testEvent("1")))
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event models.Event
+	assert.Equal(t, nil, conn.ReadJSON(&event))
+	assert.Equal(t, "1", event.EventID)
+
+	body, err := json.Marshal(testEvent("2"))
+	assert.Equal(t, nil, err)
+	resp, err := http.Post(server.URL+"/events", "application/json", bytes.NewReader(body))
+	assert.Equal(t, nil, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	assert.Equal(t, nil, conn.ReadJSON(&event))
+	assert.Equal(t, "2", event.EventID)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ExportsHandler struct {
+	service services.ExportsService
+}
+
+func NewExportsHandler(service services.ExportsService) *ExportsHandler {
+	return &ExportsHandler{
+		service: service,
+	}
+}
+
+// exportContentTypes are the content types of each export format.
+var exportContentTypes = map[models.ExportFormat]string{
+	models.ExportFormatNDJSON:  "application/x-ndjson",
+	models.ExportFormatCSV:     "text/csv",
+	models.ExportFormatParquet: "application/vnd.apache.parquet",
+}
+
+// ExportEventsHandler streams the events matching the filter in the query
+// string as they are read from storage. Once the body has started, a failure
+// can only be reported by cutting it short.
+func (h *ExportsHandler) ExportEventsHandler(c *gin.Context) {
+	filter, format, err := exportParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", exportContentTypes[format])
+	c.Header("Content-Disposition", `attachment; filename="events`+format.Extension()+`"`)
+	w := &exportResponseWriter{c: c}
+	if _, err := h.service.Export(c.Request.Context(), filter, format, w); err != nil {
+		if !w.written {
+			writeExportError(c, err)
+			return
+		}
+		slog.Warn("export broke off", "format", format, "error", err.Error())
+	}
+	if !w.written {
+		c.Status(http.StatusOK)
+	}
+}
+
+func (h *ExportsHandler) CreateExportJobHandler(c *gin.Context) {
+	filter, format, err := exportParams(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job, err := h.service.CreateExportJob(c.Request.Context(), filter, format)
+	if err != nil {
+		writeExportError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *ExportsHandler) ListExportJobsHandler(c *gin.Context) {
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, h.service.ListExportJobs(c.Request.Context()))
+}
+
+func (h *ExportsHandler) GetExportJobHandler(c *gin.Context) {
+	job, err := h.service.GetExportJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeExportError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, job)
+}
+
+// exportParams reads the format and filter of an export from the query
+// string. Unlike analytics, an export with no window, start or end covers
+// every event.
+func exportParams(c *gin.Context) (*models.EventFilter, models.ExportFormat, error) {
+	format := models.ExportFormat(c.DefaultQuery("format", string(models.ExportFormatNDJSON)))
+	if err := format.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	filter := &models.EventFilter{}
+	window, start, end := c.Query("window"), c.Query("start"), c.Query("end")
+	if window != "" || start != "" || end != "" {
+		var err error
+		if filter, err = buildFilterFromRange(window, start, end); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := applyFilterParams(filter, c.Request.URL.Query()); err != nil {
+		return nil, "", err
+	}
+	return filter, format, nil
+}
+
+// exportResponseWriter writes the status line with the first byte of the
+// export, so an error found before then can still be sent as JSON.
+type exportResponseWriter struct {
+	c       *gin.Context
+	written bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.written {
+		w.written = true
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
+}
+
+func writeExportError(c *gin.Context, err error) {
+	var invalid *services.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrExportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
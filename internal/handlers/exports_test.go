@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+func TestExportEventsHandler(t *testing.T) {
+	store := storage.NewEventStorage()
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, event := range []*models.Event{
+		{EventID: "1", UserID: "alice", EventType: models.EventTypePageView, Timestamp: &timestamp, Properties: models.EventProperties{Page: "/home"}},
+		{EventID: "2", UserID: "alice", EventType: models.EventTypeClick, Timestamp: &timestamp, Properties: models.EventProperties{Link: "/buy"}},
+	} {
+		assert.Equal(t, nil, store.Save(context.Background(), event))
+	}
+	handler := NewExportsHandler(services.NewExportsService(store, config.ExportsConfig{Directory: t.TempDir()}))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/events/export", handler.ExportEventsHandler)
+	router.POST("/events/export/jobs", handler.CreateExportJobHandler)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/export?format=csv&properties.page=/home", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="events.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "event_id,user_id,event_type,timestamp,page,amount,product_id,email,link,country,currency,name\n"+
+		"1,alice,page_view,2024-01-02T03:04:05Z,/home,,,,,,,\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/export?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events/export?event_type=bogus", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/export/jobs?format=xml", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ImportsHandler struct {
+	service services.ImportsService
+}
+
+func NewImportsHandler(service services.ImportsService) *ImportsHandler {
+	return &ImportsHandler{
+		service: service,
+	}
+}
+
+// importFormats maps the content types of an import body to its format, for
+// clients that don't pass format explicitly.
+var importFormats = map[string]models.ImportFormat{
+	"application/x-ndjson": models.ImportFormatNDJSON,
+	"application/jsonl":    models.ImportFormatNDJSON,
+	"text/csv":             models.ImportFormatCSV,
+}
+
+func (h *ImportsHandler) ImportEventsHandler(c *gin.Context) {
+	format := models.ImportFormat(c.Query("format"))
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		format = importFormats[mediaType]
+	}
+
+	job, err := h.service.Import(c.Request.Context(), c.Query("job_id"), format, c.Request.Body)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+	job.ErrorReport = errorReportPath(job.ID)
+	// The job stops where the body went over the limit, and can be resumed
+	// from there with the rest in smaller uploads.
+	if tooLarge, ok := bodyTooLarge(c); ok {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": tooLargeMessage(tooLarge), "job_id": job.ID, "records": job.Records})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *ImportsHandler) GetImportHandler(c *gin.Context) {
+	job, err := h.service.GetImport(c.Request.Context(), c.Param("job_id"))
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+	job.ErrorReport = errorReportPath(job.ID)
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, job)
+}
+
+// GetImportErrorsHandler downloads a job's error report as NDJSON, or as CSV
+// with format=csv.
+func (h *ImportsHandler) GetImportErrorsHandler(c *gin.Context) {
+	id := c.Param("job_id")
+	importErrors, err := h.service.GetImportErrors(c.Request.Context(), id)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	switch models.ImportFormat(c.DefaultQuery("format", string(models.ImportFormatNDJSON))) {
+	case models.ImportFormatNDJSON:
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Header("Content-Disposition", `attachment; filename="import-`+id+`-errors.ndjson"`)
+		c.Status(http.StatusOK)
+		encoder := json.NewEncoder(c.Writer)
+		for _, importErr := range importErrors {
+			if err := encoder.Encode(importErr); err != nil {
+				return
+			}
+		}
+	case models.ImportFormatCSV:
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", `attachment; filename="import-`+id+`-errors.csv"`)
+		c.Status(http.StatusOK)
+		writer := csv.NewWriter(c.Writer)
+		writer.Write([]string{"record", "event_id", "error"})
+		for _, importErr := range importErrors {
+			writer.Write([]string{strconv.Itoa(importErr.Record), importErr.EventID, importErr.Error})
+		}
+		writer.Flush()
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be ndjson or csv"})
+	}
+}
+
+func errorReportPath(id string) string {
+	return "/events/import/" + id + "/errors"
+}
+
+func writeImportError(c *gin.Context, err error) {
+	var invalid *services.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrImportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrImportRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+func TestImportEventsHandler(t *testing.T) {
+	store := storage.NewEventStorage()
+	handler := NewImportsHandler(services.NewImportsService(services.NewEventsService(store)))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/events/import", handler.ImportEventsHandler)
+	router.GET("/events/import/:job_id/errors", handler.GetImportErrorsHandler)
+
+	body := "event_id,user_id,event_type,timestamp,page\n" +
+		"1,alice,page_view,2024-01-02T03:04:05Z,/home\n" +
+		"2,alice,page_view,2024-01-02T03:04:05Z,\n"
+	req := httptest.NewRequest(http.MethodPost, "/events/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var job models.ImportJob
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &job))
+	assert.Equal(t, models.ImportFormatCSV, job.Format)
+	assert.Equal(t, 1, job.Accepted)
+	assert.Equal(t, 1, job.Rejected)
+	assert.Equal(t, "/events/import/"+job.ID+"/errors", job.ErrorReport)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, job.ErrorReport+"?format=csv", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "record,event_id,error\n2,2,page is required for page_view events\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/events/import", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
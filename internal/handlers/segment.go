@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// SegmentHandler serves the Segment tracking API, so clients written to it
+// can send events here unchanged. Messages are translated by
+// SegmentService.
+type SegmentHandler struct {
+	service services.SegmentService
+}
+
+func NewSegmentHandler(service services.SegmentService) *SegmentHandler {
+	return &SegmentHandler{
+		service: service,
+	}
+}
+
+func (h *SegmentHandler) TrackHandler(c *gin.Context) {
+	h.handleMessage(c, models.SegmentTypeTrack)
+}
+
+func (h *SegmentHandler) PageHandler(c *gin.Context) {
+	h.handleMessage(c, models.SegmentTypePage)
+}
+
+func (h *SegmentHandler) IdentifyHandler(c *gin.Context) {
+	h.handleMessage(c, models.SegmentTypeIdentify)
+}
+
+func (h *SegmentHandler) AliasHandler(c *gin.Context) {
+	h.handleMessage(c, models.SegmentTypeAlias)
+}
+
+// handleMessage ingests one message, whose type is set by the endpoint it
+// was sent to. Unlike in a batch, a rejected message fails the request.
+func (h *SegmentHandler) handleMessage(c *gin.Context, messageType string) {
+	var message models.SegmentMessage
+	if err := c.ShouldBindJSON(&message); err != nil {
+		writeBodyError(c, err)
+		return
+	}
+	message.Type = messageType
+
+	result, err := h.service.Ingest(c.Request.Context(), []models.SegmentMessage{message}, countryFromHeaders(c.Request.Header))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(result.Rejected) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": result.Rejected[0].Error})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *SegmentHandler) BatchHandler(c *gin.Context) {
+	var batch models.SegmentBatch
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		writeBodyError(c, err)
+		return
+	}
+	for i := range batch.Batch {
+		if batch.Batch[i].SentAt == nil {
+			batch.Batch[i].SentAt = batch.SentAt
+		}
+	}
+
+	result, err := h.service.Ingest(c.Request.Context(), batch.Batch, countryFromHeaders(c.Request.Header))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, result)
+}
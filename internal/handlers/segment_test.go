@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+func TestSegmentHandlers(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := services.NewSegmentService(services.NewEventsService(store), services.NewUsersService(store, config.AnalyticsConfig{}), config.SegmentConfig{})
+	handler := NewSegmentHandler(service)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/page", handler.PageHandler)
+	router.POST("/v1/track", handler.TrackHandler)
+	router.POST("/v1/batch", handler.BatchHandler)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := post("/v1/page", `{"messageId":"1","anonymousId":"anon","properties":{"path":"/home"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"success":true,"created":1,"duplicates":0}`, w.Body.String())
+
+	// A track event with no type of its own is stored as a custom event.
+	w = post("/v1/track", `{"messageId":"2","userId":"alice","event":"Video Playback Started"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"success":true,"created":1,"duplicates":0}`, w.Body.String())
+
+	w = post("/v1/batch", `{
+		"sentAt": "2024-01-02T12:00:05Z",
+		"batch": [
+			{"type":"track","messageId":"3","userId":"alice","event":"click","properties":{"url":"/buy"},"originalTimestamp":"2024-01-02T12:00:00Z"},
+			{"type":"page","messageId":"1","anonymousId":"anon","properties":{"path":"/home"}},
+			{"type":"track","messageId":"4","userId":"alice","event":"signup"}
+		]
+	}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result models.SegmentResult
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.Equal(t, 1, len(result.Rejected))
+	assert.Equal(t, "4", result.Rejected[0].MessageID)
+}
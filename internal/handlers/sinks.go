@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dnakolan/event-processing-service/internal/sinks"
+	"github.com/gin-gonic/gin"
+)
+
+type SinksHandler struct {
+	manager *sinks.Manager
+}
+
+func NewSinksHandler(manager *sinks.Manager) *SinksHandler {
+	return &SinksHandler{
+		manager: manager,
+	}
+}
+
+func (h *SinksHandler) ListSinksHandler(c *gin.Context) {
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, h.manager.Statuses())
+}
+
+func (h *SinksHandler) PauseSinkHandler(c *gin.Context) {
+	h.setPaused(c, true)
+}
+
+func (h *SinksHandler) ResumeSinkHandler(c *gin.Context) {
+	h.setPaused(c, false)
+}
+
+func (h *SinksHandler) setPaused(c *gin.Context, paused bool) {
+	status, err := h.manager.SetPaused(c.Param("name"), paused)
+	if errors.Is(err, sinks.ErrSinkNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type UsersHandler struct {
+	service services.UsersService
+}
+
+func NewUsersHandler(service services.UsersService) *UsersHandler {
+	return &UsersHandler{
+		service: service,
+	}
+}
+
+func (h *UsersHandler) GetUserHandler(c *gin.Context) {
+	profile, err := h.service.GetUserProfile(c.Request.Context(), c.Param("user_id"))
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *UsersHandler) GetUserTimelineHandler(c *gin.Context) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(models.DefaultTimelineLimit)))
+	if err != nil || limit <= 0 || limit > models.MaxTimelineLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+		return
+	}
+
+	timeline, err := h.service.GetUserTimeline(c.Request.Context(), c.Param("user_id"), offset, limit)
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, timeline)
+}
+
+// AliasUserHandler links the previous_id in the body, like an anonymous ID
+// the user had before signing up, to the user in the path.
+func (h *UsersHandler) AliasUserHandler(c *gin.Context) {
+	var req models.AliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	identity, err := h.service.AliasUser(c.Request.Context(), c.Param("user_id"), req.PreviousID)
+	var invalid *services.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrAliasConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, identity)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+func TestAliasUserHandler(t *testing.T) {
+	store := storage.NewEventStorage()
+	event := testEvent("1")
+	event.UserID = "user1"
+	assert.Equal(t, nil, store.Save(context.Background(), event))
+	handler := NewUsersHandler(services.NewUsersService(store, config.AnalyticsConfig{}))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/:user_id", handler.GetUserHandler)
+	router.POST("/users/:user_id/aliases", handler.AliasUserHandler)
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return w
+	}
+
+	w := post("/users/alice/aliases", `{"previous_id":"user1"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"user_id":"alice","aliases":["user1"]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/user1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var profile models.UserProfile
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &profile))
+	assert.Equal(t, "alice", profile.UserID)
+	assert.Equal(t, 1, profile.TotalEvents)
+
+	w = post("/users/bob/aliases", `{"previous_id":"user1"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = post("/users/bob/aliases", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhooksHandler struct {
+	service services.WebhooksService
+}
+
+func NewWebhooksHandler(service services.WebhooksService) *WebhooksHandler {
+	return &WebhooksHandler{
+		service: service,
+	}
+}
+
+func (h *WebhooksHandler) CreateWebhookHandler(c *gin.Context) {
+	var req models.WebhookSubscription
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(c.Request.Context(), &req)
+	if errors.Is(err, services.ErrWebhooksClosed) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *WebhooksHandler) ListWebhooksHandler(c *gin.Context) {
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, h.service.ListSubscriptions(c.Request.Context()))
+}
+
+func (h *WebhooksHandler) GetWebhookHandler(c *gin.Context) {
+	subscription, err := h.service.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, subscription)
+}
+
+func (h *WebhooksHandler) DeleteWebhookHandler(c *gin.Context) {
+	if err := h.service.DeleteSubscription(c.Request.Context(), c.Param("id")); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *WebhooksHandler) ListDeadLettersHandler(c *gin.Context) {
+	deadLetters, err := h.service.ListDeadLetters(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusOK, deadLetters)
+}
+
+func (h *WebhooksHandler) RedeliverHandler(c *gin.Context) {
+	if err := h.service.Redeliver(c.Request.Context(), c.Param("id"), c.Param("delivery_id")); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.JSON(http.StatusAccepted, gin.H{"status": "queued"})
+}
+
+func writeWebhookError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrSubscriptionNotFound) || errors.Is(err, services.ErrDeliveryNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
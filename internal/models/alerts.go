@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const DefaultBaselineWindows = 24
+
+type AlertRuleKind string
+
+const (
+	// AlertRuleThreshold compares the count in the window with Threshold.
+	AlertRuleThreshold AlertRuleKind = "threshold"
+	// AlertRuleRelative compares the count with Threshold times the count
+	// in the window it is compared against.
+	AlertRuleRelative AlertRuleKind = "relative"
+	// AlertRuleAnomaly compares the count with the mean of the preceding
+	// BaselineWindows windows, plus or minus Threshold standard deviations.
+	AlertRuleAnomaly AlertRuleKind = "anomaly"
+)
+
+type AlertDirection string
+
+const (
+	AlertAbove AlertDirection = "above"
+	AlertBelow AlertDirection = "below"
+)
+
+// AlertRule is evaluated periodically over the event counts in the trailing
+// Window. It fires once when its condition starts to hold and resolves when
+// it stops; after firing it stays quiet for Cooldown.
+type AlertRule struct {
+	Name            string         `json:"name" yaml:"name"`
+	Kind            AlertRuleKind  `json:"kind" yaml:"kind"`
+	EventType       EventType      `json:"event_type,omitempty" yaml:"event_type"`
+	Window          string         `json:"window" yaml:"window"`
+	Direction       AlertDirection `json:"direction" yaml:"direction"`
+	Threshold       float64        `json:"threshold" yaml:"threshold"`
+	Compare         CompareMode    `json:"compare,omitempty" yaml:"compare"`
+	BaselineWindows int            `json:"baseline_windows,omitempty" yaml:"baseline_windows"`
+	Cooldown        string         `json:"cooldown,omitempty" yaml:"cooldown"`
+}
+
+type AlertStatus string
+
+const (
+	AlertStatusActive   AlertStatus = "active"
+	AlertStatusResolved AlertStatus = "resolved"
+)
+
+type Alert struct {
+	ID     string      `json:"id"`
+	Rule   string      `json:"rule"`
+	Status AlertStatus `json:"status"`
+	// Value is the latest count in the rule's window, Expected the bound it
+	// crossed.
+	Value      int        `json:"value"`
+	Expected   float64    `json:"expected"`
+	Message    string     `json:"message"`
+	FiredAt    time.Time  `json:"fired_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+func (r *AlertRule) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if r.EventType != "" && !isValidEventType(string(r.EventType)) {
+		return errors.New("invalid event_type")
+	}
+	if _, err := ParseWindow(r.Window); err != nil {
+		return errors.New("invalid window")
+	}
+	if r.Direction != AlertAbove && r.Direction != AlertBelow {
+		return errors.New("direction must be above or below")
+	}
+	if r.Threshold < 0 {
+		return errors.New("threshold must not be negative")
+	}
+	if r.Cooldown != "" {
+		if _, err := ParseWindow(r.Cooldown); err != nil {
+			return errors.New("invalid cooldown")
+		}
+	}
+	switch r.Kind {
+	case AlertRuleThreshold:
+	case AlertRuleRelative:
+		if r.Compare != "" {
+			return r.Compare.Validate()
+		}
+	case AlertRuleAnomaly:
+		if r.BaselineWindows < 0 || r.BaselineWindows == 1 {
+			return errors.New("baseline_windows must be at least 2")
+		}
+	default:
+		return errors.New("kind must be threshold, relative or anomaly")
+	}
+	return nil
+}
+
+// WindowDuration returns the parsed Window of a validated rule.
+func (r *AlertRule) WindowDuration() time.Duration {
+	window, _ := ParseWindow(r.Window)
+	return window
+}
+
+// CooldownDuration returns the parsed Cooldown of a validated rule.
+func (r *AlertRule) CooldownDuration() time.Duration {
+	if r.Cooldown == "" {
+		return 0
+	}
+	cooldown, _ := ParseWindow(r.Cooldown)
+	return cooldown
+}
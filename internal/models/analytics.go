@@ -1,20 +1,320 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Granularity string
+
+const (
+	GranularityMinute     Granularity = "minute"
+	GranularityFiveMinute Granularity = "5m"
+	GranularityHour       Granularity = "hour"
+	GranularityDay        Granularity = "day"
+	GranularityWeek       Granularity = "week"
+)
 
 type Analytics struct {
-	TimeWindow    string            `json:"time_window"`
-	TotalEvents   int               `json:"total_events"`
-	EventsByType  map[EventType]int `json:"events_by_type"`
-	UniqueUsers   int               `json:"unique_users"`
-	EventsPerHour []EventPerHour    `json:"events_per_hour"`
+	TimeWindow             string            `json:"time_window"`
+	Start                  *time.Time        `json:"start,omitempty"`
+	End                    *time.Time        `json:"end,omitempty"`
+	TotalEvents            int               `json:"total_events"`
+	EventsByType           map[EventType]int `json:"events_by_type"`
+	UniqueUsers            int               `json:"unique_users"`
+	UniqueUsersApproximate bool              `json:"unique_users_approximate"`
+	UniqueUsersError       float64           `json:"unique_users_error,omitempty"`
+	EventsPerHour          []EventPerHour    `json:"events_per_hour"`
+	Granularity            Granularity       `json:"granularity,omitempty"`
+	Series                 []TimeBucket      `json:"series,omitempty"`
+	GroupBy                string            `json:"group_by,omitempty"`
+	Groups                 []AnalyticsGroup  `json:"groups,omitempty"`
+	Other                  *AnalyticsGroup   `json:"other,omitempty"`
+	Revenue                *Revenue          `json:"revenue,omitempty"`
+	Sessions               *SessionMetrics   `json:"sessions,omitempty"`
+	Stats                  []PropertyStats   `json:"stats,omitempty"`
+	Comparison             *Comparison       `json:"comparison,omitempty"`
+}
+
+// SessionMetrics describe sessions derived at query time: a user's events
+// split wherever they were inactive for longer than the session gap. A bounce
+// is a session with a single event.
+type SessionMetrics struct {
+	SessionGap            string  `json:"session_gap"`
+	Sessions              int     `json:"sessions"`
+	MeanDurationSeconds   float64 `json:"mean_duration_seconds"`
+	MedianDurationSeconds float64 `json:"median_duration_seconds"`
+	BounceRate            float64 `json:"bounce_rate"`
+	EventsPerSession      float64 `json:"events_per_session"`
+	PagesPerSession       float64 `json:"pages_per_session"`
+}
+
+// Revenue covers purchase events, converted into Currency. Purchases in a
+// currency without a configured exchange rate are only counted in
+// UnconvertedOrders.
+type Revenue struct {
+	Currency          string           `json:"currency"`
+	TotalRevenue      float64          `json:"total_revenue"`
+	Orders            int              `json:"orders"`
+	AverageOrderValue float64          `json:"average_order_value"`
+	UnconvertedOrders int              `json:"unconverted_orders,omitempty"`
+	RevenuePerHour    []RevenuePerHour `json:"revenue_per_hour"`
+	TopProducts       []ProductRevenue `json:"top_products"`
 }
 
+type RevenuePerHour struct {
+	Hour    time.Time `json:"hour"`
+	Revenue float64   `json:"revenue"`
+}
+
+type ProductRevenue struct {
+	ProductID string  `json:"product_id"`
+	Revenue   float64 `json:"revenue"`
+	Orders    int     `json:"orders"`
+}
+
+type AnalyticsGroup struct {
+	Key         string       `json:"key"`
+	TotalEvents int          `json:"total_events"`
+	UniqueUsers int          `json:"unique_users"`
+	Series      []TimeBucket `json:"series,omitempty"`
+}
+
+type AnalyticsQuery struct {
+	Filter           *EventFilter
+	ExactUniqueUsers bool
+	Granularity      Granularity
+	Location         *time.Location
+	GroupBy          string
+	Top              int
+	Sessions         bool
+	SessionGap       time.Duration
+	// Stats are property paths, such as properties.amount, to describe.
+	Stats            []string
+	HistogramBuckets int
+	Compare          CompareMode
+}
+
+const (
+	DefaultGroupTop = 10
+	MaxGroupTop     = 100
+
+	// GroupKeyUnknown collects events without a value for the group_by field.
+	GroupKeyUnknown = "unknown"
+)
+
 type EventPerHour struct {
 	Hour  time.Time `json:"hour"`
 	Count int       `json:"count"`
 }
 
+type TimeBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+var windowUnits = map[byte]time.Duration{
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// ParseWindow extends time.ParseDuration with whole day (d) and week (w)
+// units, e.g. "7d" or "2w".
+func ParseWindow(window string) (time.Duration, error) {
+	if window == "" {
+		return 0, errors.New("invalid window")
+	}
+	if unit, ok := windowUnits[window[len(window)-1]]; ok {
+		n, err := strconv.Atoi(window[:len(window)-1])
+		if err != nil || n <= 0 {
+			return 0, errors.New("invalid window")
+		}
+		return time.Duration(n) * unit, nil
+	}
+	timeWindow, err := time.ParseDuration(window)
+	if err != nil || timeWindow <= 0 {
+		return 0, errors.New("invalid window")
+	}
+	return timeWindow, nil
+}
+
+// RangeFilter builds a filter over either window, ending at end or now when
+// end is nil, or explicit start and end bounds.
+func RangeFilter(window string, start, end *time.Time) (*EventFilter, error) {
+	if window == "" && start == nil {
+		return nil, errors.New("window or start is required")
+	}
+	if window != "" && start != nil {
+		return nil, errors.New("window and start are mutually exclusive")
+	}
+
+	endTime := time.Now()
+	if end != nil {
+		endTime = *end
+	}
+	var startTime time.Time
+	if window != "" {
+		timeWindow, err := ParseWindow(window)
+		if err != nil {
+			return nil, err
+		}
+		startTime = endTime.Add(-timeWindow)
+	} else {
+		startTime = *start
+	}
+
+	filter := &EventFilter{
+		StartTimestamp: &startTime,
+		EndTimestamp:   &endTime,
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// MaxSeriesBuckets bounds how many buckets a single analytics series may have.
+const MaxSeriesBuckets = 10000
+
+func (q *AnalyticsQuery) Validate() error {
+	if q.Filter != nil {
+		if err := q.Filter.Validate(); err != nil {
+			return err
+		}
+	}
+	if q.GroupBy != "" && !isGroupBy(q.GroupBy) {
+		return errors.New("invalid group_by")
+	}
+	if q.Top < 0 || q.Top > MaxGroupTop {
+		return errors.New("top must be between 1 and 100")
+	}
+	if q.SessionGap < 0 {
+		return errors.New("session_gap must be greater than 0")
+	}
+	for _, path := range q.Stats {
+		if _, err := StatsField(path); err != nil {
+			return err
+		}
+	}
+	if q.HistogramBuckets < 0 || q.HistogramBuckets > MaxHistogramBuckets {
+		return errors.New("histogram_buckets must be between 1 and 100")
+	}
+	if q.Compare != "" {
+		if err := q.Compare.Validate(); err != nil {
+			return err
+		}
+		if q.Filter == nil || q.Filter.StartTimestamp == nil || q.Filter.EndTimestamp == nil {
+			return errors.New("start and end are required to compare")
+		}
+	}
+	if q.Granularity == "" {
+		return nil
+	}
+	if err := q.Granularity.Validate(); err != nil {
+		return err
+	}
+	if q.Filter == nil || q.Filter.StartTimestamp == nil || q.Filter.EndTimestamp == nil {
+		return errors.New("start and end are required for a series")
+	}
+	span := q.Filter.EndTimestamp.Sub(*q.Filter.StartTimestamp)
+	if span/q.Granularity.approximateDuration() > MaxSeriesBuckets {
+		return errors.New("too many buckets for granularity, use a coarser one")
+	}
+	return nil
+}
+
+// GroupKey returns the value of the group_by field for an event: event_type,
+// user_id, a property such as properties.page, or the derived country.
+func GroupKey(event *Event, groupBy string) string {
+	var key string
+	switch groupBy {
+	case "event_type":
+		key = string(event.EventType)
+	case "user_id":
+		key = event.UserID
+	case "country":
+		key = strings.ToUpper(event.Properties.Country)
+	default:
+		key, _ = event.Property(strings.TrimPrefix(groupBy, "properties."))
+	}
+	if key == "" {
+		return GroupKeyUnknown
+	}
+	return key
+}
+
+func isGroupBy(groupBy string) bool {
+	switch groupBy {
+	case "event_type", "user_id", "country":
+		return true
+	}
+	field, ok := strings.CutPrefix(groupBy, "properties.")
+	return ok && isPropertyField(field)
+}
+
+func (g Granularity) Validate() error {
+	switch g {
+	case GranularityMinute, GranularityFiveMinute, GranularityHour, GranularityDay, GranularityWeek:
+		return nil
+	default:
+		return errors.New("invalid granularity")
+	}
+}
+
+// Truncate returns the start of the bucket containing t. Hours, days and
+// weeks follow the wall clock of loc, and weeks start on Monday.
+func (g Granularity) Truncate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch g {
+	case GranularityMinute:
+		return t.Truncate(time.Minute)
+	case GranularityFiveMinute:
+		return t.Truncate(5 * time.Minute)
+	case GranularityHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case GranularityDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	case GranularityWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return t
+}
+
+func (g Granularity) approximateDuration() time.Duration {
+	switch g {
+	case GranularityMinute:
+		return time.Minute
+	case GranularityFiveMinute:
+		return 5 * time.Minute
+	case GranularityHour:
+		return time.Hour
+	case GranularityDay:
+		return 24 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
+
+// Next returns the start of the bucket following the one starting at start.
+func (g Granularity) Next(start time.Time) time.Time {
+	switch g {
+	case GranularityMinute:
+		return start.Add(time.Minute)
+	case GranularityFiveMinute:
+		return start.Add(5 * time.Minute)
+	case GranularityHour:
+		return start.Add(time.Hour)
+	case GranularityDay:
+		return start.AddDate(0, 0, 1)
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	}
+	return start
+}
+
 /*
 {
   "time_window": "24h",
@@ -25,9 +325,23 @@ type EventPerHour struct {
     "purchase": 100
   },
   "unique_users": 45,
+  "unique_users_approximate": true,
+  "unique_users_error": 0.008125,
   "events_per_hour": [
     {"hour": "2025-05-26T14:00:00Z", "count": 120},
     {"hour": "2025-05-26T15:00:00Z", "count": 95}
-  ]
+  ],
+  "revenue": {
+    "currency": "USD",
+    "total_revenue": 2999.5,
+    "orders": 100,
+    "average_order_value": 29.99,
+    "revenue_per_hour": [
+      {"hour": "2025-05-26T14:00:00Z", "revenue": 1799.7}
+    ],
+    "top_products": [
+      {"product_id": "xyz", "revenue": 1499.5, "orders": 50}
+    ]
+  }
 }
 */
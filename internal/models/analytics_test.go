@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		window      string
+		expected    time.Duration
+		expectError bool
+	}{
+		{name: "hours", window: "24h", expected: 24 * time.Hour},
+		{name: "days", window: "7d", expected: 7 * 24 * time.Hour},
+		{name: "weeks", window: "2w", expected: 14 * 24 * time.Hour},
+		{name: "zero days", window: "0d", expectError: true},
+		{name: "negative", window: "-1h", expectError: true},
+		{name: "garbage", window: "xd", expectError: true},
+		{name: "empty", window: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := ParseWindow(tt.window)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, window)
+		})
+	}
+}
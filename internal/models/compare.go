@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// CompareMode picks the range a query is compared against.
+type CompareMode string
+
+const (
+	ComparePreviousPeriod CompareMode = "previous_period"
+	ComparePreviousWeek   CompareMode = "previous_week"
+)
+
+// Comparison holds the previous value of each headline metric, and the
+// change from it to the current value.
+type Comparison struct {
+	Compare      CompareMode               `json:"compare"`
+	Start        time.Time                 `json:"start"`
+	End          time.Time                 `json:"end"`
+	TotalEvents  MetricDelta               `json:"total_events"`
+	EventsByType map[EventType]MetricDelta `json:"events_by_type"`
+	UniqueUsers  MetricDelta               `json:"unique_users"`
+}
+
+// MetricDelta compares a metric with its previous value. PercentChange is
+// left out when the previous value is zero.
+type MetricDelta struct {
+	Previous      int      `json:"previous"`
+	Delta         int      `json:"delta"`
+	PercentChange *float64 `json:"percent_change,omitempty"`
+}
+
+func (m CompareMode) Validate() error {
+	switch m {
+	case ComparePreviousPeriod, ComparePreviousWeek:
+		return nil
+	default:
+		return errors.New("compare must be previous_period or previous_week")
+	}
+}
+
+// Range returns the range [start, end] is compared against: the same length
+// immediately before it, or the same times a week earlier. Both ranges are
+// inclusive, so the previous period ends just before start.
+func (m CompareMode) Range(start, end time.Time) (time.Time, time.Time) {
+	shift := end.Sub(start) + time.Nanosecond
+	if m == ComparePreviousWeek {
+		shift = 7 * 24 * time.Hour
+	}
+	return start.Add(-shift), end.Add(-shift)
+}
+
+func NewMetricDelta(current, previous int) MetricDelta {
+	delta := MetricDelta{
+		Previous: previous,
+		Delta:    current - previous,
+	}
+	if previous != 0 {
+		percent := float64(delta.Delta) / float64(previous) * 100
+		delta.PercentChange = &percent
+	}
+	return delta
+}
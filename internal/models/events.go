@@ -2,6 +2,8 @@ package models
 
 import (
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,6 +14,9 @@ const (
 	EventTypeClick    EventType = "click"
 	EventTypePurchase EventType = "purchase"
 	EventTypeSignup   EventType = "signup"
+	// EventTypeCustom is an event of a kind the service has no type for,
+	// named by its name property.
+	EventTypeCustom EventType = "custom"
 )
 
 type Event struct {
@@ -32,13 +37,36 @@ type EventProperties struct {
 	ProductID string  `json:"product_id"`
 	Email     string  `json:"email"`
 	Link      string  `json:"link"`
+	Country   string  `json:"country,omitempty"`
+	Currency  string  `json:"currency,omitempty"`
+	Name      string  `json:"name,omitempty"`
 }
 
 type EventFilter struct {
-	UserID         *string    `json:"user_id"`
-	EventType      *EventType `json:"event_type"`
-	StartTimestamp *time.Time `json:"start_timestamp"`
-	EndTimestamp   *time.Time `json:"end_timestamp"`
+	UserID         *string             `json:"user_id"`
+	EventType      *EventType          `json:"event_type"`
+	StartTimestamp *time.Time          `json:"start_timestamp"`
+	EndTimestamp   *time.Time          `json:"end_timestamp"`
+	Properties     []PropertyPredicate `json:"properties"`
+}
+
+type PredicateOp string
+
+const (
+	PredicateOpEq       PredicateOp = "eq"
+	PredicateOpNe       PredicateOp = "ne"
+	PredicateOpGt       PredicateOp = "gt"
+	PredicateOpGte      PredicateOp = "gte"
+	PredicateOpLt       PredicateOp = "lt"
+	PredicateOpLte      PredicateOp = "lte"
+	PredicateOpContains PredicateOp = "contains"
+	PredicateOpPrefix   PredicateOp = "prefix"
+)
+
+type PropertyPredicate struct {
+	Field string      `json:"field"`
+	Op    PredicateOp `json:"op"`
+	Value string      `json:"value"`
 }
 
 func (f *EventFilter) Validate() error {
@@ -53,9 +81,62 @@ func (f *EventFilter) Validate() error {
 			return errors.New("start_timestamp must be before end_timestamp")
 		}
 	}
+	for _, predicate := range f.Properties {
+		if err := predicate.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (p *PropertyPredicate) Validate() error {
+	if !isPropertyField(p.Field) {
+		return errors.New("unknown property " + p.Field)
+	}
+	switch p.Op {
+	case PredicateOpEq, PredicateOpNe, PredicateOpContains, PredicateOpPrefix:
+		return nil
+	case PredicateOpGt, PredicateOpGte, PredicateOpLt, PredicateOpLte:
+		if _, err := strconv.ParseFloat(p.Value, 64); err != nil {
+			return errors.New("value for " + string(p.Op) + " on " + p.Field + " must be a number")
+		}
+		return nil
+	default:
+		return errors.New("invalid op " + string(p.Op))
+	}
+}
+
+func (p *PropertyPredicate) Matches(e *Event) bool {
+	value, _ := e.Property(p.Field)
+	switch p.Op {
+	case PredicateOpEq:
+		return value == p.Value
+	case PredicateOpNe:
+		return value != p.Value
+	case PredicateOpContains:
+		return strings.Contains(value, p.Value)
+	case PredicateOpPrefix:
+		return strings.HasPrefix(value, p.Value)
+	}
+
+	actual, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	expected, _ := strconv.ParseFloat(p.Value, 64)
+	switch p.Op {
+	case PredicateOpGt:
+		return actual > expected
+	case PredicateOpGte:
+		return actual >= expected
+	case PredicateOpLt:
+		return actual < expected
+	case PredicateOpLte:
+		return actual <= expected
+	}
+	return false
+}
+
 func (e *CreateEventRequest) Validate() error {
 	if err := e.Event.Validate(); err != nil {
 		return err
@@ -89,12 +170,15 @@ func (e *Event) validatePropertiesForType(eventType EventType) error {
 			return errors.New("page is required for page_view events")
 		}
 	case EventTypePurchase:
-		if e.Properties.Amount >= 0 {
+		if e.Properties.Amount <= 0 {
 			return errors.New("amount must be greater than 0 for purchase events")
 		}
 		if e.Properties.ProductID == "" {
 			return errors.New("product_id is required for purchase events")
 		}
+		if e.Properties.Currency != "" && !isCurrencyCode(e.Properties.Currency) {
+			return errors.New("currency must be a three letter ISO 4217 code")
+		}
 	case EventTypeSignup:
 		if e.Properties.Email == "" {
 			return errors.New("email is required for signup events")
@@ -103,6 +187,10 @@ func (e *Event) validatePropertiesForType(eventType EventType) error {
 		if e.Properties.Link == "" {
 			return errors.New("link is required for click events")
 		}
+	case EventTypeCustom:
+		if e.Properties.Name == "" {
+			return errors.New("name is required for custom events")
+		}
 	}
 	return nil
 }
@@ -117,18 +205,79 @@ func (e *Event) MatchesFilter(filter *EventFilter) bool {
 	if filter.EventType != nil && *filter.EventType != "" && *filter.EventType != e.EventType {
 		return false
 	}
+	// An event without a timestamp can't be placed in a time range.
+	if (filter.StartTimestamp != nil || filter.EndTimestamp != nil) && e.Timestamp == nil {
+		return false
+	}
 	if filter.StartTimestamp != nil && e.Timestamp.Before(*filter.StartTimestamp) {
 		return false
 	}
 	if filter.EndTimestamp != nil && e.Timestamp.After(*filter.EndTimestamp) {
 		return false
 	}
+	for _, predicate := range filter.Properties {
+		if !predicate.Matches(e) {
+			return false
+		}
+	}
 	return true
 }
 
+// Property returns a property by its JSON name, formatted as a string, and
+// whether the event has a non-empty value for it.
+func (e *Event) Property(field string) (string, bool) {
+	var value string
+	switch field {
+	case "page":
+		value = e.Properties.Page
+	case "amount":
+		if e.Properties.Amount != 0 {
+			value = strconv.FormatFloat(e.Properties.Amount, 'f', -1, 64)
+		}
+	case "product_id":
+		value = e.Properties.ProductID
+	case "email":
+		value = e.Properties.Email
+	case "link":
+		value = e.Properties.Link
+	case "country":
+		value = e.Properties.Country
+	case "currency":
+		value = e.Properties.Currency
+	case "name":
+		value = e.Properties.Name
+	}
+	return value, value != ""
+}
+
+func isPropertyField(field string) bool {
+	switch field {
+	case "page", "amount", "product_id", "email", "link", "country", "currency", "name":
+		return true
+	default:
+		return false
+	}
+}
+
+func isCurrencyCode(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+func (t EventType) Valid() bool {
+	return isValidEventType(string(t))
+}
+
 func isValidEventType(eventType string) bool {
 	switch EventType(eventType) {
-	case EventTypePageView, EventTypeClick, EventTypePurchase, EventTypeSignup:
+	case EventTypePageView, EventTypeClick, EventTypePurchase, EventTypeSignup, EventTypeCustom:
 		return true
 	default:
 		return false
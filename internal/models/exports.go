@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatNDJSON  ExportFormat = "ndjson"
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+type ExportStatus string
+
+const (
+	ExportStatusRunning   ExportStatus = "running"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportJob is an export written to the exports directory in the background.
+// Path is only set once the file is complete.
+type ExportJob struct {
+	ID          string       `json:"job_id"`
+	Format      ExportFormat `json:"format"`
+	Filter      EventFilter  `json:"filter"`
+	Status      ExportStatus `json:"status"`
+	Events      int          `json:"events"`
+	Path        string       `json:"path,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+}
+
+func (f ExportFormat) Validate() error {
+	switch f {
+	case ExportFormatNDJSON, ExportFormatCSV, ExportFormatParquet:
+		return nil
+	default:
+		return errors.New("format must be ndjson, csv or parquet")
+	}
+}
+
+// Extension is the file extension for an export in this format.
+func (f ExportFormat) Extension() string {
+	return "." + string(f)
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	MinFunnelSteps = 2
+	MaxFunnelSteps = 10
+)
+
+type FunnelRequest struct {
+	Steps            []FunnelStep `json:"steps"`
+	ConversionWindow string       `json:"conversion_window"`
+	Window           string       `json:"window"`
+	Start            *time.Time   `json:"start"`
+	End              *time.Time   `json:"end"`
+}
+
+type FunnelStep struct {
+	Name       string              `json:"name"`
+	EventType  EventType           `json:"event_type"`
+	Properties []PropertyPredicate `json:"properties"`
+}
+
+type Funnel struct {
+	ConversionWindow string             `json:"conversion_window"`
+	Start            time.Time          `json:"start"`
+	End              time.Time          `json:"end"`
+	Steps            []FunnelStepResult `json:"steps"`
+}
+
+type FunnelStepResult struct {
+	Name      string    `json:"name"`
+	EventType EventType `json:"event_type"`
+	Users     int       `json:"users"`
+	// ConversionRate is relative to the first step, StepConversionRate to
+	// the previous one.
+	ConversionRate      float64  `json:"conversion_rate"`
+	StepConversionRate  float64  `json:"step_conversion_rate"`
+	MedianSecondsToStep *float64 `json:"median_seconds_to_step,omitempty"`
+}
+
+func (r *FunnelRequest) Validate() error {
+	if len(r.Steps) < MinFunnelSteps || len(r.Steps) > MaxFunnelSteps {
+		return errors.New("a funnel needs between 2 and 10 steps")
+	}
+	for _, step := range r.Steps {
+		if err := step.Validate(); err != nil {
+			return err
+		}
+	}
+	if _, err := ParseWindow(r.ConversionWindow); err != nil {
+		return errors.New("invalid conversion_window")
+	}
+	if r.Window != "" && r.Start != nil {
+		return errors.New("window and start are mutually exclusive")
+	}
+	if r.Window == "" && r.Start == nil {
+		return errors.New("window or start is required")
+	}
+	if r.Window != "" {
+		if _, err := ParseWindow(r.Window); err != nil {
+			return err
+		}
+	}
+	if r.Start != nil && r.End != nil && r.Start.After(*r.End) {
+		return errors.New("start must be before end")
+	}
+	return nil
+}
+
+// Range resolves the period in which users must enter the funnel, with end
+// defaulting to now.
+func (r *FunnelRequest) Range(now time.Time) (time.Time, time.Time) {
+	end := now
+	if r.End != nil {
+		end = *r.End
+	}
+	if r.Start != nil {
+		return *r.Start, end
+	}
+	window, _ := ParseWindow(r.Window)
+	return end.Add(-window), end
+}
+
+func (s *FunnelStep) Validate() error {
+	if !isValidEventType(string(s.EventType)) {
+		return errors.New("invalid event_type in funnel step")
+	}
+	for _, predicate := range s.Properties {
+		if err := predicate.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *FunnelStep) Matches(e *Event) bool {
+	if e.EventType != s.EventType {
+		return false
+	}
+	for _, predicate := range s.Properties {
+		if !predicate.Matches(e) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunnelRequest_Validate(t *testing.T) {
+	steps := []FunnelStep{
+		{EventType: EventTypePageView},
+		{EventType: EventTypeSignup},
+	}
+
+	tests := []struct {
+		name          string
+		req           FunnelRequest
+		expectedError string
+	}{
+		{
+			name:          "single step",
+			req:           FunnelRequest{Steps: steps[:1], ConversionWindow: "1h", Window: "7d"},
+			expectedError: "a funnel needs between 2 and 10 steps",
+		},
+		{
+			name:          "missing conversion window",
+			req:           FunnelRequest{Steps: steps, Window: "7d"},
+			expectedError: "invalid conversion_window",
+		},
+		{
+			name:          "missing range",
+			req:           FunnelRequest{Steps: steps, ConversionWindow: "1d"},
+			expectedError: "window or start is required",
+		},
+		{
+			name: "invalid step",
+			req: FunnelRequest{
+				Steps:            []FunnelStep{{EventType: "scroll"}, steps[1]},
+				ConversionWindow: "1d",
+				Window:           "7d",
+			},
+			expectedError: "invalid event_type in funnel step",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
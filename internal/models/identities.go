@@ -0,0 +1,14 @@
+package models
+
+// Identity is a canonical user and the other IDs, like the anonymous IDs of
+// visits before they signed up, that their events were sent under.
+type Identity struct {
+	UserID  string   `json:"user_id"`
+	Aliases []string `json:"aliases"`
+}
+
+// AliasRequest links PreviousID to the user in the path, merging the events
+// already stored under it into theirs.
+type AliasRequest struct {
+	PreviousID string `json:"previous_id"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatNDJSON ImportFormat = "ndjson"
+	ImportFormatCSV    ImportFormat = "csv"
+)
+
+type ImportStatus string
+
+const (
+	ImportStatusRunning ImportStatus = "running"
+	// ImportStatusInterrupted means the upload broke off; it can be resumed
+	// from record Records+1.
+	ImportStatusInterrupted ImportStatus = "interrupted"
+	ImportStatusCompleted   ImportStatus = "completed"
+)
+
+// MaxImportErrors caps the errors kept for a job's report. Later rejections
+// are still counted.
+const MaxImportErrors = 10000
+
+// ImportJob tracks a bulk import across one or more uploads. Records counts
+// the records read so far, numbered from 1 across all the uploads, and every
+// record is accepted, rejected or a duplicate of an event already stored.
+type ImportJob struct {
+	ID              string       `json:"job_id"`
+	Format          ImportFormat `json:"format"`
+	Status          ImportStatus `json:"status"`
+	Records         int          `json:"records"`
+	Accepted        int          `json:"accepted"`
+	Rejected        int          `json:"rejected"`
+	Duplicates      int          `json:"duplicates"`
+	ErrorsTruncated bool         `json:"errors_truncated,omitempty"`
+	ErrorReport     string       `json:"error_report,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// ImportError is one line of a job's error report.
+type ImportError struct {
+	Record  int    `json:"record"`
+	EventID string `json:"event_id,omitempty"`
+	Error   string `json:"error"`
+}
+
+func (f ImportFormat) Validate() error {
+	switch f {
+	case ImportFormatNDJSON, ImportFormatCSV:
+		return nil
+	default:
+		return errors.New("format must be ndjson or csv")
+	}
+}
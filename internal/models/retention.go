@@ -0,0 +1,77 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultRetentionDays  = 14
+	DefaultRetentionWeeks = 8
+	MaxRetentionPeriods   = 90
+)
+
+type RetentionQuery struct {
+	Cohort      Granularity
+	Start       time.Time
+	End         time.Time
+	CohortEvent EventType
+	ReturnEvent EventType
+	Periods     int
+	Location    *time.Location
+}
+
+type Retention struct {
+	Cohort      Granularity       `json:"cohort"`
+	CohortEvent EventType         `json:"cohort_event,omitempty"`
+	ReturnEvent EventType         `json:"return_event,omitempty"`
+	Start       time.Time         `json:"start"`
+	End         time.Time         `json:"end"`
+	Cohorts     []RetentionCohort `json:"cohorts"`
+}
+
+// RetentionCohort holds, for each period since the cohort started (period 0
+// being the cohort's own day or week), how many of its users came back with a
+// qualifying event. Periods that have not started yet are left out.
+type RetentionCohort struct {
+	Start    time.Time `json:"start"`
+	Users    int       `json:"users"`
+	Retained []int     `json:"retained"`
+	Rates    []float64 `json:"rates"`
+}
+
+func (q *RetentionQuery) Validate() error {
+	if q.Cohort != GranularityDay && q.Cohort != GranularityWeek {
+		return errors.New("cohort must be day or week")
+	}
+	if q.Start.After(q.End) {
+		return errors.New("start must be before end")
+	}
+	if q.CohortEvent != "" && !isValidEventType(string(q.CohortEvent)) {
+		return errors.New("invalid cohort_event")
+	}
+	if q.ReturnEvent != "" && !isValidEventType(string(q.ReturnEvent)) {
+		return errors.New("invalid return_event")
+	}
+	if q.Periods < 1 || q.Periods > MaxRetentionPeriods {
+		return errors.New("periods must be between 1 and 90")
+	}
+	if q.End.Sub(q.Start)/q.Cohort.approximateDuration() > MaxRetentionPeriods {
+		return errors.New("too many cohorts, use a shorter range or weekly cohorts")
+	}
+	return nil
+}
+
+// PeriodsBetween counts whole days or weeks, on the wall clock of loc, from
+// the bucket containing from to the bucket containing to.
+func (g Granularity) PeriodsBetween(from, to time.Time, loc *time.Location) int {
+	a := g.Truncate(from, loc)
+	b := g.Truncate(to, loc)
+	// Compare civil dates so DST changes don't shorten a day.
+	days := int(time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+	if g == GranularityWeek {
+		return days / 7
+	}
+	return days
+}
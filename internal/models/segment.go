@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// SegmentMessage is a call from a client written to the Segment tracking
+// API spec. Only the fields mapped onto events are read.
+type SegmentMessage struct {
+	Type              string         `json:"type"`
+	MessageID         string         `json:"messageId"`
+	UserID            string         `json:"userId"`
+	AnonymousID       string         `json:"anonymousId"`
+	PreviousID        string         `json:"previousId"`
+	Event             string         `json:"event"`
+	Name              string         `json:"name"`
+	Properties        map[string]any `json:"properties"`
+	Traits            map[string]any `json:"traits"`
+	Context           SegmentContext `json:"context"`
+	Timestamp         *time.Time     `json:"timestamp"`
+	OriginalTimestamp *time.Time     `json:"originalTimestamp"`
+	SentAt            *time.Time     `json:"sentAt"`
+}
+
+type SegmentContext struct {
+	Page   SegmentPage    `json:"page"`
+	Traits map[string]any `json:"traits"`
+}
+
+type SegmentPage struct {
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// SegmentBatch is the body of /v1/batch. SentAt applies to the messages
+// that don't have their own.
+type SegmentBatch struct {
+	Batch  []SegmentMessage `json:"batch"`
+	SentAt *time.Time       `json:"sentAt"`
+}
+
+const (
+	SegmentTypeTrack    = "track"
+	SegmentTypePage     = "page"
+	SegmentTypeIdentify = "identify"
+	SegmentTypeAlias    = "alias"
+)
+
+// SegmentResult is the response to a Segment call. Success is always true,
+// as Segment clients expect; the counts say what became of the messages.
+type SegmentResult struct {
+	Success    bool               `json:"success"`
+	Created    int                `json:"created"`
+	Duplicates int                `json:"duplicates"`
+	Rejected   []SegmentRejection `json:"rejected,omitempty"`
+}
+
+type SegmentRejection struct {
+	MessageID string `json:"messageId,omitempty"`
+	Error     string `json:"error"`
+}
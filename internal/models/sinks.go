@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SinkStatus reports the health of a sink. Delivered, Failed and Dropped
+// count events: failed ones exhausted their retries, dropped ones arrived
+// while the buffer was full.
+type SinkStatus struct {
+	Name        string     `json:"name"`
+	Type        string     `json:"type"`
+	Paused      bool       `json:"paused"`
+	Healthy     bool       `json:"healthy"`
+	Buffered    int        `json:"buffered"`
+	Delivered   int        `json:"delivered"`
+	Failed      int        `json:"failed"`
+	Dropped     int        `json:"dropped"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	LastFlushAt *time.Time `json:"last_flush_at,omitempty"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	DefaultHistogramBuckets = 10
+	MaxHistogramBuckets     = 100
+)
+
+// NumericProperties are the properties that distribution statistics can be
+// computed over.
+var NumericProperties = []string{"amount"}
+
+// PropertyStats describe the distribution of a numeric property over the
+// events that have it. Percentiles and histogram counts are estimates when
+// Approximate is set, within PercentileError of the true values.
+type PropertyStats struct {
+	Property        string            `json:"property"`
+	Count           int               `json:"count"`
+	Min             float64           `json:"min"`
+	Max             float64           `json:"max"`
+	Mean            float64           `json:"mean"`
+	StdDev          float64           `json:"stddev"`
+	P50             float64           `json:"p50"`
+	P90             float64           `json:"p90"`
+	P95             float64           `json:"p95"`
+	P99             float64           `json:"p99"`
+	Approximate     bool              `json:"approximate"`
+	PercentileError float64           `json:"percentile_error,omitempty"`
+	Histogram       []HistogramBucket `json:"histogram"`
+}
+
+// HistogramBucket counts values in [Start, End); the last bucket also
+// includes End.
+type HistogramBucket struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Count int     `json:"count"`
+}
+
+// NumericProperty returns a numeric property by its JSON name and whether
+// the event has a value for it.
+func (e *Event) NumericProperty(field string) (float64, bool) {
+	switch field {
+	case "amount":
+		return e.Properties.Amount, e.Properties.Amount != 0
+	}
+	return 0, false
+}
+
+// StatsField returns the property name of a stats path such as
+// properties.amount.
+func StatsField(path string) (string, error) {
+	field, ok := strings.CutPrefix(path, "properties.")
+	if !ok || !isNumericProperty(field) {
+		return "", errors.New("stats must name a numeric property, such as properties.amount")
+	}
+	return field, nil
+}
+
+func isNumericProperty(field string) bool {
+	for _, numeric := range NumericProperties {
+		if field == numeric {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	DefaultTopLimit = 10
+	MaxTopLimit     = 100
+)
+
+// TopDimension is what a top-N query ranks, each over a single kind of event.
+type TopDimension string
+
+const (
+	TopDimensionPages    TopDimension = "pages"
+	TopDimensionLinks    TopDimension = "links"
+	TopDimensionUsers    TopDimension = "users"
+	TopDimensionProducts TopDimension = "products"
+)
+
+// TopDimensions lists every dimension, in a stable order.
+var TopDimensions = []TopDimension{
+	TopDimensionPages,
+	TopDimensionLinks,
+	TopDimensionUsers,
+	TopDimensionProducts,
+}
+
+type TopMode string
+
+const (
+	// TopModeAuto counts exactly while the window holds few enough events.
+	TopModeAuto        TopMode = "auto"
+	TopModeExact       TopMode = "exact"
+	TopModeApproximate TopMode = "approximate"
+)
+
+type TopQuery struct {
+	Dimension TopDimension
+	Start     time.Time
+	End       time.Time
+	Limit     int
+	Mode      TopMode
+}
+
+type TopItems struct {
+	Dimension TopDimension `json:"dimension"`
+	Start     time.Time    `json:"start"`
+	End       time.Time    `json:"end"`
+	// Approximate is set when counts come from heavy-hitter sketches that
+	// had to drop keys. Each count then overestimates by at most its error.
+	Approximate bool      `json:"approximate"`
+	Items       []TopItem `json:"items"`
+}
+
+type TopItem struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+	Error int    `json:"error,omitempty"`
+}
+
+func (q *TopQuery) Validate() error {
+	if !q.Dimension.isValid() {
+		return errors.New("dimension must be one of pages, links, users or products")
+	}
+	if q.Limit <= 0 || q.Limit > MaxTopLimit {
+		return errors.New("limit must be between 1 and 100")
+	}
+	switch q.Mode {
+	case TopModeAuto, TopModeExact, TopModeApproximate:
+	default:
+		return errors.New("mode must be auto, exact or approximate")
+	}
+	if q.Start.After(q.End) {
+		return errors.New("start must be before end")
+	}
+	return nil
+}
+
+func (d TopDimension) isValid() bool {
+	for _, dimension := range TopDimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns the value e is counted under for the dimension, or false if
+// the dimension doesn't count e.
+func (d TopDimension) Key(e *Event) (string, bool) {
+	switch d {
+	case TopDimensionPages:
+		return e.Properties.Page, e.EventType == EventTypePageView && e.Properties.Page != ""
+	case TopDimensionLinks:
+		return e.Properties.Link, e.EventType == EventTypeClick && e.Properties.Link != ""
+	case TopDimensionUsers:
+		return e.UserID, e.UserID != ""
+	case TopDimensionProducts:
+		return e.Properties.ProductID, e.EventType == EventTypePurchase && e.Properties.ProductID != ""
+	}
+	return "", false
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+const (
+	DefaultTimelineLimit = 50
+	MaxTimelineLimit     = 500
+)
+
+// UserProfile is built from the events of every ID the user is known by.
+// UserID is the canonical one, whichever was asked for.
+type UserProfile struct {
+	UserID              string            `json:"user_id"`
+	Aliases             []string          `json:"aliases,omitempty"`
+	FirstSeen           time.Time         `json:"first_seen"`
+	LastSeen            time.Time         `json:"last_seen"`
+	TotalEvents         int               `json:"total_events"`
+	EventsByType        map[EventType]int `json:"events_by_type"`
+	TotalPurchaseAmount float64           `json:"total_purchase_amount"`
+	Currency            string            `json:"currency"`
+	LastPageViewed      string            `json:"last_page_viewed,omitempty"`
+}
+
+type UserTimeline struct {
+	UserID     string           `json:"user_id"`
+	Events     []*TimelineEvent `json:"events"`
+	Total      int              `json:"total"`
+	Offset     int              `json:"offset"`
+	Limit      int              `json:"limit"`
+	NextOffset *int             `json:"next_offset,omitempty"`
+}
+
+// TimelineEvent is an event with the ID of the session it belongs to. An
+// event without a timestamp has no session.
+type TimelineEvent struct {
+	*Event
+	SessionID string `json:"session_id,omitempty"`
+}
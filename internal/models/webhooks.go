@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultWebhookBatchSize     = 100
+	MaxWebhookBatchSize         = 1000
+	DefaultWebhookFlushInterval = "5s"
+	MinWebhookSecretLength      = 16
+)
+
+// WebhookSubscription pushes stored events matching Filter to URL, in
+// batches of up to BatchSize or whatever has arrived within FlushInterval.
+type WebhookSubscription struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url"`
+	Filter        *EventFilter `json:"filter,omitempty"`
+	BatchSize     int          `json:"batch_size"`
+	FlushInterval string       `json:"flush_interval"`
+	// Secret signs each request. It is only returned when the subscription
+	// is created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Status is reported by the service and ignored in requests.
+	Status *WebhookStatus `json:"status,omitempty"`
+}
+
+// WebhookStatus is how delivery to a subscription is keeping up. Events
+// that arrive while its buffer is full are dropped and counted.
+type WebhookStatus struct {
+	Buffered    int `json:"buffered"`
+	Dropped     int `json:"dropped"`
+	DeadLetters int `json:"dead_letters"`
+}
+
+// WebhookDelivery is one batch of events sent to a subscription. Deliveries
+// that exhaust their attempts are kept as dead letters.
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	Events         []*Event   `json:"events"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	FailedAt       *time.Time `json:"failed_at,omitempty"`
+}
+
+// WebhookPayload is the request body sent to a subscription.
+type WebhookPayload struct {
+	DeliveryID     string   `json:"delivery_id"`
+	SubscriptionID string   `json:"subscription_id"`
+	Events         []*Event `json:"events"`
+}
+
+// Validate checks a subscription as requested, where a zero BatchSize or
+// empty FlushInterval or Secret mean the default.
+func (s *WebhookSubscription) Validate() error {
+	target, err := url.Parse(s.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if s.Filter != nil {
+		if err := s.Filter.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.BatchSize < 0 || s.BatchSize > MaxWebhookBatchSize {
+		return errors.New("batch_size must be between 1 and 1000")
+	}
+	if s.FlushInterval != "" {
+		if interval, err := time.ParseDuration(s.FlushInterval); err != nil || interval <= 0 {
+			return errors.New("flush_interval must be a positive duration")
+		}
+	}
+	if s.Secret != "" && len(s.Secret) < MinWebhookSecretLength {
+		return errors.New("secret must be at least 16 characters")
+	}
+	return nil
+}
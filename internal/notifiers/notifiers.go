@@ -0,0 +1,113 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// Notifier delivers an alert when it fires or resolves.
+type Notifier interface {
+	Notify(ctx context.Context, alert *models.Alert) error
+}
+
+type logNotifier struct{}
+
+func NewLogNotifier() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Notify(_ context.Context, alert *models.Alert) error {
+	slog.Warn("alert "+string(alert.Status),
+		"rule", alert.Rule,
+		"value", alert.Value,
+		"expected", alert.Expected,
+		"message", alert.Message,
+	)
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier POSTs each alert as JSON to url.
+func NewWebhookNotifier(url string) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, alert *models.Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// StreamNotifier fans alerts out to subscribers, such as WebSocket clients.
+// A subscriber that falls behind misses alerts rather than blocking others.
+type StreamNotifier struct {
+	mutex       sync.RWMutex
+	subscribers map[chan *models.Alert]bool
+}
+
+func NewStreamNotifier() *StreamNotifier {
+	return &StreamNotifier{
+		subscribers: make(map[chan *models.Alert]bool),
+	}
+}
+
+func (n *StreamNotifier) Notify(_ context.Context, alert *models.Alert) error {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	for subscriber := range n.subscribers {
+		select {
+		case subscriber <- alert:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a channel of alerts and a function to unsubscribe,
+// which closes the channel.
+func (n *StreamNotifier) Subscribe() (<-chan *models.Alert, func()) {
+	subscriber := make(chan *models.Alert, 16)
+	n.mutex.Lock()
+	n.subscribers[subscriber] = true
+	n.mutex.Unlock()
+
+	return subscriber, func() {
+		n.mutex.Lock()
+		defer n.mutex.Unlock()
+		if n.subscribers[subscriber] {
+			delete(n.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	received := make(chan models.Alert, 1)
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert models.Alert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		received <- alert
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	alert := &models.Alert{ID: "a", Rule: "click_flood", Status: models.AlertStatusActive, Value: 3, FiredAt: time.Now().UTC()}
+
+	require.NoError(t, notifier.Notify(context.Background(), alert))
+	assert.Equal(t, *alert, <-received)
+
+	status = http.StatusInternalServerError
+	assert.Error(t, notifier.Notify(context.Background(), alert))
+}
+
+func TestStreamNotifier_Subscribe(t *testing.T) {
+	notifier := NewStreamNotifier()
+	alerts, unsubscribe := notifier.Subscribe()
+
+	alert := &models.Alert{ID: "a"}
+	require.NoError(t, notifier.Notify(context.Background(), alert))
+	assert.Equal(t, alert, <-alerts)
+
+	unsubscribe()
+	_, ok := <-alerts
+	assert.False(t, ok)
+	require.NoError(t, notifier.Notify(context.Background(), alert))
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/notifiers"
+	"github.com/google/uuid"
+)
+
+// maxAlertHistory caps how many alerts, oldest first, are kept.
+const maxAlertHistory = 1000
+
+type AlertsService interface {
+	// Run evaluates every rule each interval until ctx is done.
+	Run(ctx context.Context)
+	ListAlerts(ctx context.Context, status models.AlertStatus) []models.Alert
+	ListRules(ctx context.Context) []models.AlertRule
+}
+
+type alertsService struct {
+	analytics AnalyticsService
+	config    config.AlertsConfig
+	notifiers []notifiers.Notifier
+
+	mutex  sync.RWMutex
+	alerts []*models.Alert
+	// state tracks each rule by name.
+	state map[string]*ruleState
+}
+
+type ruleState struct {
+	active    *models.Alert
+	lastFired time.Time
+}
+
+func NewAlertsService(analytics AnalyticsService, config config.AlertsConfig, notifiers ...notifiers.Notifier) AlertsService {
+	return &alertsService{
+		analytics: analytics,
+		config:    config,
+		notifiers: notifiers,
+		state:     make(map[string]*ruleState),
+	}
+}
+
+func (s *alertsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.evaluate(ctx, now)
+		}
+	}
+}
+
+func (s *alertsService) ListAlerts(_ context.Context, status models.AlertStatus) []models.Alert {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	alerts := make([]models.Alert, 0, len(s.alerts))
+	for i := len(s.alerts) - 1; i >= 0; i-- {
+		if status == "" || s.alerts[i].Status == status {
+			alerts = append(alerts, *s.alerts[i])
+		}
+	}
+	return alerts
+}
+
+func (s *alertsService) ListRules(_ context.Context) []models.AlertRule {
+	return s.config.Rules
+}
+
+// evaluate checks every rule at now, firing and resolving alerts, and then
+// notifies about the changes.
+func (s *alertsService) evaluate(ctx context.Context, now time.Time) {
+	var changed []models.Alert
+	s.mutex.Lock()
+	for i := range s.config.Rules {
+		rule := &s.config.Rules[i]
+		value, expected, breached := s.check(rule, now)
+		if alert := s.transition(rule, now, value, expected, breached); alert != nil {
+			changed = append(changed, *alert)
+		}
+	}
+	s.mutex.Unlock()
+
+	for i := range changed {
+		for _, notifier := range s.notifiers {
+			if err := notifier.Notify(ctx, &changed[i]); err != nil {
+				slog.Error("failed to notify alert", "rule", changed[i].Rule, "error", err.Error())
+			}
+		}
+	}
+}
+
+// transition applies one evaluation of rule and returns the alert it fired
+// or resolved, if any. A rule that is still breached keeps its active alert
+// rather than firing again, and stays quiet for its cooldown after firing.
+func (s *alertsService) transition(rule *models.AlertRule, now time.Time, value int, expected float64, breached bool) *models.Alert {
+	state, ok := s.state[rule.Name]
+	if !ok {
+		state = &ruleState{}
+		s.state[rule.Name] = state
+	}
+
+	if !breached {
+		if state.active == nil {
+			return nil
+		}
+		alert := state.active
+		alert.Status = models.AlertStatusResolved
+		alert.Value = value
+		alert.ResolvedAt = &now
+		state.active = nil
+		return alert
+	}
+	if state.active != nil {
+		state.active.Value = value
+		state.active.Expected = expected
+		return nil
+	}
+	if !state.lastFired.IsZero() && now.Before(state.lastFired.Add(rule.CooldownDuration())) {
+		return nil
+	}
+
+	alert := &models.Alert{
+		ID:       uuid.New().String(),
+		Rule:     rule.Name,
+		Status:   models.AlertStatusActive,
+		Value:    value,
+		Expected: expected,
+		Message:  alertMessage(rule, value, expected),
+		FiredAt:  now,
+	}
+	state.active = alert
+	state.lastFired = now
+	s.alerts = append(s.alerts, alert)
+	if len(s.alerts) > maxAlertHistory {
+		s.alerts = s.alerts[len(s.alerts)-maxAlertHistory:]
+	}
+	return alert
+}
+
+// check returns the count in the rule's trailing window, the bound it is
+// compared with and whether it crosses that bound. Rules without a baseline
+// to compare with are never breached.
+func (s *alertsService) check(rule *models.AlertRule, now time.Time) (int, float64, bool) {
+	window := rule.WindowDuration()
+	value := s.analytics.CountEvents(rule.EventType, now.Add(-window), now)
+
+	var expected float64
+	switch rule.Kind {
+	case models.AlertRuleThreshold:
+		expected = rule.Threshold
+	case models.AlertRuleRelative:
+		shift := window
+		if rule.Compare == "" || rule.Compare == models.ComparePreviousWeek {
+			shift = 7 * 24 * time.Hour
+		}
+		previous := s.analytics.CountEvents(rule.EventType, now.Add(-shift-window), now.Add(-shift))
+		if previous == 0 {
+			return value, 0, false
+		}
+		expected = rule.Threshold * float64(previous)
+	case models.AlertRuleAnomaly:
+		windows := rule.BaselineWindows
+		if windows == 0 {
+			windows = models.DefaultBaselineWindows
+		}
+		var sum, sumSquares float64
+		for i := 1; i <= windows; i++ {
+			end := now.Add(-time.Duration(i) * window)
+			count := float64(s.analytics.CountEvents(rule.EventType, end.Add(-window), end))
+			sum += count
+			sumSquares += count * count
+		}
+		if sum == 0 {
+			return value, 0, false
+		}
+		mean := sum / float64(windows)
+		stddev := math.Sqrt(math.Max(0, sumSquares/float64(windows)-mean*mean))
+		expected = mean + rule.Threshold*stddev
+		if rule.Direction == models.AlertBelow {
+			expected = mean - rule.Threshold*stddev
+		}
+	}
+
+	if rule.Direction == models.AlertAbove {
+		return value, expected, float64(value) > expected
+	}
+	return value, expected, float64(value) < expected
+}
+
+func alertMessage(rule *models.AlertRule, value int, expected float64) string {
+	eventType := string(rule.EventType)
+	if eventType == "" {
+		eventType = "event"
+	}
+	return fmt.Sprintf("%s count in the last %s is %d, %s the expected %.2f", eventType, rule.Window, value, rule.Direction, expected)
+}
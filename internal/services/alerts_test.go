@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	mutex  sync.Mutex
+	alerts []models.Alert
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, alert *models.Alert) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.alerts = append(n.alerts, *alert)
+	return nil
+}
+
+func TestAlertsService_Threshold(t *testing.T) {
+	store := storage.NewEventStorage()
+	analytics := NewAnalyticsService(store, config.AnalyticsConfig{})
+	notifier := &recordingNotifier{}
+	service := NewAlertsService(analytics, config.AlertsConfig{
+		Rules: []models.AlertRule{{
+			Name:      "click_flood",
+			Kind:      models.AlertRuleThreshold,
+			EventType: models.EventTypeClick,
+			Window:    "15m",
+			Direction: models.AlertAbove,
+			Threshold: 2,
+			Cooldown:  "1h",
+		}},
+	}, notifier).(*alertsService)
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Save(ctx, newTestEvent("123", models.EventTypeClick, t0.Add(time.Duration(i)*time.Minute))))
+	}
+
+	service.evaluate(ctx, t0.Add(5*time.Minute))
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, models.AlertStatusActive, notifier.alerts[0].Status)
+	assert.Equal(t, 3, notifier.alerts[0].Value)
+
+	// Still breached, so deduplicated.
+	service.evaluate(ctx, t0.Add(6*time.Minute))
+	assert.Len(t, notifier.alerts, 1)
+
+	// The clicks leave the window.
+	service.evaluate(ctx, t0.Add(20*time.Minute))
+	require.Len(t, notifier.alerts, 2)
+	assert.Equal(t, models.AlertStatusResolved, notifier.alerts[1].Status)
+	assert.Equal(t, notifier.alerts[0].ID, notifier.alerts[1].ID)
+
+	// Breached again, but inside the cooldown.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, store.Save(ctx, newTestEvent("123", models.EventTypeClick, t0.Add(25*time.Minute))))
+	}
+	service.evaluate(ctx, t0.Add(30*time.Minute))
+	assert.Len(t, notifier.alerts, 2)
+
+	assert.Len(t, service.ListAlerts(ctx, models.AlertStatusResolved), 1)
+	assert.Empty(t, service.ListAlerts(ctx, models.AlertStatusActive))
+}
+
+func TestAlertsService_Baselines(t *testing.T) {
+	store := storage.NewEventStorage()
+	analytics := NewAnalyticsService(store, config.AnalyticsConfig{})
+	ctx := context.Background()
+	now := time.Date(2025, 6, 8, 12, 0, 0, 0, time.UTC)
+
+	// Four purchases in the same 15 minutes last week, one now.
+	for i := 0; i < 4; i++ {
+		require.NoError(t, store.Save(ctx, newTestEvent("123", models.EventTypePurchase, now.Add(-7*24*time.Hour-time.Duration(i+1)*time.Minute))))
+	}
+	require.NoError(t, store.Save(ctx, newTestEvent("123", models.EventTypePurchase, now.Add(-time.Minute))))
+	// Hourly signups alternating between 4 and 6, then a spike of 10.
+	for hour := 1; hour <= 24; hour++ {
+		count := 4 + 2*(hour%2)
+		for i := 0; i < count; i++ {
+			require.NoError(t, store.Save(ctx, newTestEvent("123", models.EventTypeSignup, now.Add(-time.Duration(hour)*time.Hour-time.Minute))))
+		}
+	}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, store.Save(ctx, newTestEvent("123", models.EventTypeSignup, now.Add(-time.Minute))))
+	}
+
+	tests := []struct {
+		name     string
+		rule     models.AlertRule
+		expected float64
+		breached bool
+	}{
+		{
+			name: "purchase rate below half of last week",
+			rule: models.AlertRule{
+				Name: "purchases", Kind: models.AlertRuleRelative, EventType: models.EventTypePurchase,
+				Window: "15m", Direction: models.AlertBelow, Threshold: 0.5, Compare: models.ComparePreviousWeek,
+			},
+			expected: 2,
+			breached: true,
+		},
+		{
+			name: "signups three sigma above the hourly baseline",
+			rule: models.AlertRule{
+				Name: "signups", Kind: models.AlertRuleAnomaly, EventType: models.EventTypeSignup,
+				Window: "1h", Direction: models.AlertAbove, Threshold: 3, BaselineWindows: 24,
+			},
+			expected: 8,
+			breached: true,
+		},
+		{
+			name: "signups four sigma below",
+			rule: models.AlertRule{
+				Name: "signups", Kind: models.AlertRuleAnomaly, EventType: models.EventTypeSignup,
+				Window: "1h", Direction: models.AlertBelow, Threshold: 4,
+			},
+			expected: 1,
+			breached: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.rule.Validate())
+			service := NewAlertsService(analytics, config.AlertsConfig{}).(*alertsService)
+			_, expected, breached := service.check(&tt.rule, now)
+			assert.InDelta(t, tt.expected, expected, 1e-9)
+			assert.Equal(t, tt.breached, breached)
+		})
+	}
+}
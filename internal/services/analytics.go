@@ -3,36 +3,235 @@ package services
 import (
 	"context"
 	"sort"
+	"sync"
 	"time"
 
+	"github.com/dnakolan/event-processing-service/internal/aggregation"
+	"github.com/dnakolan/event-processing-service/internal/config"
 	"github.com/dnakolan/event-processing-service/internal/models"
 	"github.com/dnakolan/event-processing-service/internal/storage"
 )
 
 type AnalyticsService interface {
-	GetAnalytics(ctx context.Context, filter *models.EventFilter) (*models.Analytics, error)
+	GetAnalytics(ctx context.Context, query *models.AnalyticsQuery) (*models.Analytics, error)
+	StreamAnalytics(ctx context.Context, opts StreamOptions) (<-chan *models.Analytics, error)
+	GetFunnel(ctx context.Context, req *models.FunnelRequest) (*models.Funnel, error)
+	GetRetention(ctx context.Context, query *models.RetentionQuery) (*models.Retention, error)
+	GetTop(ctx context.Context, query *models.TopQuery) (*models.TopItems, error)
+	// CountEvents counts events of a type, or of any type if it is empty, in
+	// the half-open range [from, to).
+	CountEvents(eventType models.EventType, from, to time.Time) int
 }
 
 type analyticsService struct {
-	storage storage.EventStorage
+	storage   storage.EventStorage
+	config    config.AnalyticsConfig
+	converter *aggregation.CurrencyConverter
+	rollups   *aggregation.Rollups
+
+	mutex   sync.RWMutex
+	windows map[*liveWindow]bool
+}
+
+func NewAnalyticsService(storage storage.EventStorage, config config.AnalyticsConfig) AnalyticsService {
+	converter := aggregation.NewCurrencyConverter(config.BaseCurrency, config.ExchangeRates)
+	s := &analyticsService{
+		storage:   storage,
+		config:    config,
+		converter: converter,
+		rollups:   aggregation.NewRollups(converter),
+		windows:   make(map[*liveWindow]bool),
+	}
+	storage.AddObserver(s.rollups)
+	storage.AddObserver(s)
+	return s
+}
+
+func (s *analyticsService) GetAnalytics(ctx context.Context, query *models.AnalyticsQuery) (*models.Analytics, error) {
+	filter := query.Filter
+	var start, end *time.Time
+	if filter != nil {
+		start, end = filter.StartTimestamp, filter.EndTimestamp
+	}
+
+	var analytics *models.Analytics
+	var events []*models.Event
+	var count func(from, to time.Time) int
+	if isTimeRangeOnly(filter) && query.GroupBy == "" {
+		summary := s.rollups.Query(start, end)
+		analytics = analyticsFromSummary(summary)
+		analytics.Revenue = revenueFromSummary(summary, s.converter.Base())
+		// Exact counting walks every event in the window, so it is only
+		// honoured while the window is small enough.
+		if query.ExactUniqueUsers && summary.TotalEvents <= s.config.ExactUniqueLimit {
+			analytics.UniqueUsers = s.rollups.DistinctUsers(start, end)
+		} else {
+			analytics.UniqueUsers = summary.Users.Count()
+			analytics.UniqueUsersApproximate = true
+			analytics.UniqueUsersError = aggregation.HLLRelativeError
+		}
+		count = s.rollups.Count
+		for _, path := range query.Stats {
+			field, _ := models.StatsField(path)
+			sketch := s.rollups.Stats(field, start, end)
+			analytics.Stats = append(analytics.Stats, statsFromSketch(path, sketch, histogramBuckets(query)))
+		}
+	} else {
+		var err error
+		if events, err = s.storage.FindAll(ctx, filter); err != nil {
+			return nil, err
+		}
+		analytics = &models.Analytics{
+			TotalEvents:   len(events),
+			EventsByType:  eventsByType(events),
+			UniqueUsers:   uniqueUsers(events),
+			EventsPerHour: eventsPerHour(events),
+			Revenue:       revenueFromEvents(events, s.converter),
+		}
+		count = countInRange(events)
+		for _, path := range query.Stats {
+			field, _ := models.StatsField(path)
+			analytics.Stats = append(analytics.Stats, statsFromEvents(path, field, events, histogramBuckets(query)))
+		}
+		if query.GroupBy != "" {
+			analytics.GroupBy = query.GroupBy
+			analytics.Groups, analytics.Other = groupEvents(events, query)
+		}
+	}
+
+	// Sessions need every event in the window, which the rollups can't give.
+	if query.Sessions {
+		if events == nil {
+			var err error
+			if events, err = s.storage.FindAll(ctx, filter); err != nil {
+				return nil, err
+			}
+		}
+		gap := query.SessionGap
+		if gap == 0 {
+			gap = s.config.SessionGap
+		}
+		analytics.Sessions = sessionMetrics(events, gap)
+	}
+
+	analytics.Start, analytics.End = start, end
+	if query.Granularity != "" {
+		analytics.Granularity = query.Granularity
+		analytics.Series = buildSeries(*start, *end, query.Granularity, query.Location, count)
+	}
+	if query.Compare != "" {
+		var err error
+		if analytics.Comparison, err = s.compare(ctx, query, analytics); err != nil {
+			return nil, err
+		}
+	}
+	return analytics, nil
 }
 
-func NewAnalyticsService(storage storage.EventStorage) AnalyticsService {
-	return &analyticsService{storage: storage}
+func (s *analyticsService) CountEvents(eventType models.EventType, from, to time.Time) int {
+	if eventType == "" {
+		return s.rollups.Count(from, to)
+	}
+	return s.rollups.CountType(eventType, from, to)
 }
 
-func (s *analyticsService) GetAnalytics(ctx context.Context, filter *models.EventFilter) (*models.Analytics, error) {
-	events, err := s.storage.FindAll(ctx, filter)
+// compare reruns the headline metrics of query over the range it is compared
+// against, which takes the rollups path whenever the original query would.
+func (s *analyticsService) compare(ctx context.Context, query *models.AnalyticsQuery, current *models.Analytics) (*models.Comparison, error) {
+	previousStart, previousEnd := query.Compare.Range(*query.Filter.StartTimestamp, *query.Filter.EndTimestamp)
+	filter := *query.Filter
+	filter.StartTimestamp, filter.EndTimestamp = &previousStart, &previousEnd
+
+	previous, err := s.GetAnalytics(ctx, &models.AnalyticsQuery{
+		Filter:           &filter,
+		ExactUniqueUsers: query.ExactUniqueUsers,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	comparison := &models.Comparison{
+		Compare:      query.Compare,
+		Start:        previousStart,
+		End:          previousEnd,
+		TotalEvents:  models.NewMetricDelta(current.TotalEvents, previous.TotalEvents),
+		EventsByType: make(map[models.EventType]models.MetricDelta),
+		UniqueUsers:  models.NewMetricDelta(current.UniqueUsers, previous.UniqueUsers),
+	}
+	for eventType, count := range current.EventsByType {
+		comparison.EventsByType[eventType] = models.NewMetricDelta(count, previous.EventsByType[eventType])
+	}
+	for eventType, count := range previous.EventsByType {
+		if _, ok := current.EventsByType[eventType]; !ok {
+			comparison.EventsByType[eventType] = models.NewMetricDelta(0, count)
+		}
+	}
+	return comparison, nil
+}
+
+func histogramBuckets(query *models.AnalyticsQuery) int {
+	if query.HistogramBuckets == 0 {
+		return models.DefaultHistogramBuckets
+	}
+	return query.HistogramBuckets
+}
+
+// buildSeries splits [start, end] into buckets of the given granularity,
+// including empty ones. The first and last buckets are clipped to the range.
+func buildSeries(start, end time.Time, granularity models.Granularity, loc *time.Location, count func(from, to time.Time) int) []models.TimeBucket {
+	if loc == nil {
+		loc = time.UTC
+	}
+	limit := end.Add(time.Nanosecond)
+	series := make([]models.TimeBucket, 0)
+	for bucket := granularity.Truncate(start, loc); bucket.Before(limit); bucket = granularity.Next(bucket) {
+		from, to := bucket, granularity.Next(bucket)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(limit) {
+			to = limit
+		}
+		series = append(series, models.TimeBucket{
+			Start: bucket,
+			Count: count(from, to),
+		})
+	}
+	return series
+}
+
+func countInRange(events []*models.Event) func(from, to time.Time) int {
+	timestamps := make([]time.Time, len(events))
+	for i, event := range events {
+		timestamps[i] = *event.Timestamp
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+	return func(from, to time.Time) int {
+		lo := sort.Search(len(timestamps), func(i int) bool { return !timestamps[i].Before(from) })
+		hi := sort.Search(len(timestamps), func(i int) bool { return !timestamps[i].Before(to) })
+		return hi - lo
+	}
+}
+
+// isTimeRangeOnly reports whether the filter can be answered from the rollups,
+// which are only partitioned by time.
+func isTimeRangeOnly(filter *models.EventFilter) bool {
+	if filter == nil {
+		return true
+	}
+	return (filter.UserID == nil || *filter.UserID == "") &&
+		(filter.EventType == nil || *filter.EventType == "") &&
+		len(filter.Properties) == 0
+}
+
+func analyticsFromSummary(summary *aggregation.Summary) *models.Analytics {
 	return &models.Analytics{
-		TotalEvents:   len(events),
-		EventsByType:  eventsByType(events),
-		UniqueUsers:   uniqueUsers(events),
-		EventsPerHour: eventsPerHour(events),
-	}, nil
+		TotalEvents:   summary.TotalEvents,
+		EventsByType:  summary.EventsByType,
+		EventsPerHour: sortedHours(summary.Hours),
+	}
 }
 
 func eventsByType(events []*models.Event) map[models.EventType]int {
@@ -57,8 +256,11 @@ func eventsPerHour(events []*models.Event) []models.EventPerHour {
 		hour := event.Timestamp.Truncate(time.Hour)
 		eventsPerHourMap[hour]++
 	}
+	return sortedHours(eventsPerHourMap)
+}
 
-	eventsPerHour := make([]models.EventPerHour, 0)
+func sortedHours(eventsPerHourMap map[time.Time]int) []models.EventPerHour {
+	eventsPerHour := make([]models.EventPerHour, 0, len(eventsPerHourMap))
 	for hour, count := range eventsPerHourMap {
 		eventsPerHour = append(eventsPerHour, models.EventPerHour{
 			Hour:  hour,
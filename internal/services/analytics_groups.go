@@ -0,0 +1,61 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// groupEvents breaks events down by the query's group_by field, returning the
+// top groups by event count and everything else folded into an "other" group.
+func groupEvents(events []*models.Event, query *models.AnalyticsQuery) ([]models.AnalyticsGroup, *models.AnalyticsGroup) {
+	byKey := make(map[string][]*models.Event)
+	for _, event := range events {
+		key := models.GroupKey(event, query.GroupBy)
+		byKey[key] = append(byKey[key], event)
+	}
+
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(byKey[keys[i]]) != len(byKey[keys[j]]) {
+			return len(byKey[keys[i]]) > len(byKey[keys[j]])
+		}
+		return keys[i] < keys[j]
+	})
+
+	top := query.Top
+	if top == 0 {
+		top = models.DefaultGroupTop
+	}
+
+	groups := make([]models.AnalyticsGroup, 0, min(top, len(keys)))
+	for _, key := range keys[:min(top, len(keys))] {
+		groups = append(groups, buildGroup(key, byKey[key], query))
+	}
+	if len(keys) <= top {
+		return groups, nil
+	}
+
+	rest := make([]*models.Event, 0)
+	for _, key := range keys[top:] {
+		rest = append(rest, byKey[key]...)
+	}
+	other := buildGroup("other", rest, query)
+	return groups, &other
+}
+
+func buildGroup(key string, events []*models.Event, query *models.AnalyticsQuery) models.AnalyticsGroup {
+	group := models.AnalyticsGroup{
+		Key:         key,
+		TotalEvents: len(events),
+		UniqueUsers: uniqueUsers(events),
+	}
+	if query.Granularity != "" {
+		filter := query.Filter
+		group.Series = buildSeries(*filter.StartTimestamp, *filter.EndTimestamp, query.Granularity, query.Location, countInRange(events))
+	}
+	return group
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/aggregation"
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+const topProductsLimit = 10
+
+func revenueFromSummary(summary *aggregation.Summary, currency string) *models.Revenue {
+	return buildRevenue(currency, summary.Revenue, summary.Orders, summary.UnconvertedOrders, summary.RevenueHours, summary.Products)
+}
+
+func revenueFromEvents(events []*models.Event, converter *aggregation.CurrencyConverter) *models.Revenue {
+	var total float64
+	var orders, unconverted int
+	hours := make(map[time.Time]float64)
+	products := make(map[string]*aggregation.ProductTotals)
+	for _, event := range events {
+		if event.EventType != models.EventTypePurchase {
+			continue
+		}
+		amount, ok := converter.Convert(event.Properties.Amount, event.Properties.Currency)
+		if !ok {
+			unconverted++
+			continue
+		}
+		total += amount
+		orders++
+		hours[event.Timestamp.UTC().Truncate(time.Hour)] += amount
+		product, ok := products[event.Properties.ProductID]
+		if !ok {
+			product = &aggregation.ProductTotals{}
+			products[event.Properties.ProductID] = product
+		}
+		product.Revenue += amount
+		product.Orders++
+	}
+	return buildRevenue(converter.Base(), total, orders, unconverted, hours, products)
+}
+
+func buildRevenue(currency string, total float64, orders, unconverted int, hours map[time.Time]float64, products map[string]*aggregation.ProductTotals) *models.Revenue {
+	revenue := &models.Revenue{
+		Currency:          currency,
+		TotalRevenue:      roundCents(total),
+		Orders:            orders,
+		UnconvertedOrders: unconverted,
+		RevenuePerHour:    make([]models.RevenuePerHour, 0, len(hours)),
+		TopProducts:       make([]models.ProductRevenue, 0, min(len(products), topProductsLimit)),
+	}
+	if orders > 0 {
+		revenue.AverageOrderValue = roundCents(total / float64(orders))
+	}
+
+	for hour, amount := range hours {
+		revenue.RevenuePerHour = append(revenue.RevenuePerHour, models.RevenuePerHour{
+			Hour:    hour,
+			Revenue: roundCents(amount),
+		})
+	}
+	sort.Slice(revenue.RevenuePerHour, func(i, j int) bool {
+		return revenue.RevenuePerHour[i].Hour.Before(revenue.RevenuePerHour[j].Hour)
+	})
+
+	ranked := make([]models.ProductRevenue, 0, len(products))
+	for productID, totals := range products {
+		ranked = append(ranked, models.ProductRevenue{
+			ProductID: productID,
+			Revenue:   roundCents(totals.Revenue),
+			Orders:    totals.Orders,
+		})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Revenue != ranked[j].Revenue {
+			return ranked[i].Revenue > ranked[j].Revenue
+		}
+		return ranked[i].ProductID < ranked[j].ProductID
+	})
+	revenue.TopProducts = append(revenue.TopProducts, ranked[:min(len(ranked), topProductsLimit)]...)
+	return revenue
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dnakolan/event-processing-service/internal/aggregation"
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// statsFromSketch describes a property from the rollups' merged sketch, so
+// percentiles and the histogram are estimates.
+func statsFromSketch(path string, sketch *aggregation.DDSketch, buckets int) models.PropertyStats {
+	stats := models.PropertyStats{
+		Property:  path,
+		Count:     sketch.Count(),
+		Histogram: make([]models.HistogramBucket, 0),
+	}
+	if stats.Count == 0 {
+		return stats
+	}
+	stats.Min, stats.Max = sketch.Min(), sketch.Max()
+	stats.Mean, stats.StdDev = sketch.Mean(), sketch.StdDev()
+	stats.P50 = sketch.Quantile(0.5)
+	stats.P90 = sketch.Quantile(0.9)
+	stats.P95 = sketch.Quantile(0.95)
+	stats.P99 = sketch.Quantile(0.99)
+	stats.Approximate = true
+	stats.PercentileError = aggregation.DDSketchRelativeError
+	for _, bucket := range sketch.Histogram(buckets) {
+		stats.Histogram = append(stats.Histogram, models.HistogramBucket{
+			Start: bucket.Start,
+			End:   bucket.End,
+			Count: bucket.Count,
+		})
+	}
+	return stats
+}
+
+// statsFromEvents describes a property exactly from the matching events.
+func statsFromEvents(path, field string, events []*models.Event, buckets int) models.PropertyStats {
+	stats := models.PropertyStats{
+		Property:  path,
+		Histogram: make([]models.HistogramBucket, 0),
+	}
+	values := make([]float64, 0, len(events))
+	for _, event := range events {
+		if value, ok := event.NumericProperty(field); ok {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return stats
+	}
+	sort.Float64s(values)
+
+	var sum, sumSquares float64
+	for _, value := range values {
+		sum += value
+		sumSquares += value * value
+	}
+	n := float64(len(values))
+	stats.Count = len(values)
+	stats.Min, stats.Max = values[0], values[len(values)-1]
+	stats.Mean = sum / n
+	stats.StdDev = math.Sqrt(math.Max(0, sumSquares/n-stats.Mean*stats.Mean))
+	percentile := func(q float64) float64 {
+		return values[int(q*float64(len(values)-1))]
+	}
+	stats.P50 = percentile(0.5)
+	stats.P90 = percentile(0.9)
+	stats.P95 = percentile(0.95)
+	stats.P99 = percentile(0.99)
+
+	width := (stats.Max - stats.Min) / float64(buckets)
+	stats.Histogram = make([]models.HistogramBucket, buckets)
+	for i := range stats.Histogram {
+		stats.Histogram[i].Start = stats.Min + float64(i)*width
+		stats.Histogram[i].End = stats.Min + float64(i+1)*width
+	}
+	stats.Histogram[buckets-1].End = stats.Max
+	for _, value := range values {
+		i := buckets - 1
+		if width > 0 {
+			i = min(buckets-1, int((value-stats.Min)/width))
+		}
+		stats.Histogram[i].Count++
+	}
+	return stats
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+type StreamOptions struct {
+	Window   time.Duration
+	Interval time.Duration
+	EveryN   int
+}
+
+func (s *analyticsService) StreamAnalytics(ctx context.Context, opts StreamOptions) (<-chan *models.Analytics, error) {
+	if opts.Window <= 0 {
+		return nil, errors.New("window must be greater than 0")
+	}
+	if opts.Interval == 0 {
+		opts.Interval = s.config.PushInterval
+	}
+	if opts.Interval <= 0 && opts.EveryN <= 0 {
+		return nil, errors.New("interval or every must be greater than 0")
+	}
+
+	// Register before seeding so nothing saved in between is missed; events
+	// seen twice are ignored by the window.
+	window := newLiveWindow(opts.Window, opts.EveryN)
+	s.mutex.Lock()
+	s.windows[window] = true
+	s.mutex.Unlock()
+
+	start := time.Now().Add(-opts.Window)
+	events, err := s.storage.FindAll(ctx, &models.EventFilter{StartTimestamp: &start})
+	if err != nil {
+		s.removeWindow(window)
+		return nil, err
+	}
+	for _, event := range events {
+		window.add(event)
+	}
+	window.resetTrigger()
+
+	updates := make(chan *models.Analytics, 1)
+	go s.pushUpdates(ctx, window, opts.Interval, updates)
+	return updates, nil
+}
+
+func (s *analyticsService) pushUpdates(ctx context.Context, window *liveWindow, interval time.Duration, updates chan *models.Analytics) {
+	defer close(updates)
+	defer s.removeWindow(window)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		// Slow consumers only ever see the latest state.
+		select {
+		case <-updates:
+		default:
+		}
+		updates <- window.snapshot(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-window.ready:
+		}
+	}
+}
+
+func (s *analyticsService) removeWindow(window *liveWindow) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.windows, window)
+}
+
+func (s *analyticsService) EventSaved(event, previous *models.Event) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for window := range s.windows {
+		if previous != nil {
+			window.remove(previous)
+		}
+		window.add(event)
+	}
+}
+
+func (s *analyticsService) EventDeleted(event *models.Event) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for window := range s.windows {
+		window.remove(event)
+	}
+}
+
+func (s *analyticsService) EventsCleared() {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for window := range s.windows {
+		window.clear()
+	}
+}
+
+// liveWindow keeps running aggregates over a sliding time window. Events are
+// added as they are saved and evicted lazily, oldest first, when a snapshot
+// is taken.
+type liveWindow struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	everyN  int
+	pending int
+	ready   chan struct{}
+
+	queue        eventQueue
+	members      map[string]*queuedEvent
+	eventsByType map[models.EventType]int
+	users        map[string]int
+	hours        map[time.Time]int
+}
+
+func newLiveWindow(window time.Duration, everyN int) *liveWindow {
+	w := &liveWindow{
+		window: window,
+		everyN: everyN,
+		ready:  make(chan struct{}, 1),
+	}
+	w.reset()
+	return w
+}
+
+func (w *liveWindow) reset() {
+	w.queue = nil
+	w.members = make(map[string]*queuedEvent)
+	w.eventsByType = make(map[models.EventType]int)
+	w.users = make(map[string]int)
+	w.hours = make(map[time.Time]int)
+	w.pending = 0
+}
+
+func (w *liveWindow) add(event *models.Event) {
+	if event.Timestamp == nil {
+		return
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if event.Timestamp.Before(time.Now().Add(-w.window)) {
+		return
+	}
+	existing, ok := w.members[event.EventID]
+	if ok && existing.event == event {
+		return
+	}
+	if ok {
+		w.removeLocked(existing)
+	}
+
+	queued := &queuedEvent{event: event}
+	w.members[event.EventID] = queued
+	heap.Push(&w.queue, queued)
+	w.eventsByType[event.EventType]++
+	w.users[event.UserID]++
+	w.hours[event.Timestamp.Truncate(time.Hour)]++
+
+	if w.everyN > 0 {
+		w.pending++
+		if w.pending >= w.everyN {
+			w.pending = 0
+			select {
+			case w.ready <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (w *liveWindow) remove(event *models.Event) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if queued, ok := w.members[event.EventID]; ok && queued.event == event {
+		w.removeLocked(queued)
+	}
+}
+
+// resetTrigger discards any pending "every N events" signal, since the next
+// snapshot covers everything added so far anyway.
+func (w *liveWindow) resetTrigger() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.pending = 0
+	select {
+	case <-w.ready:
+	default:
+	}
+}
+
+func (w *liveWindow) clear() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.reset()
+}
+
+// removeLocked takes an event out of the window before it expires, as when
+// it is deleted or replaced.
+func (w *liveWindow) removeLocked(queued *queuedEvent) {
+	heap.Remove(&w.queue, queued.index)
+	w.forgetLocked(queued.event)
+}
+
+func (w *liveWindow) forgetLocked(event *models.Event) {
+	delete(w.members, event.EventID)
+	decrement(w.eventsByType, event.EventType)
+	decrement(w.users, event.UserID)
+	decrement(w.hours, event.Timestamp.Truncate(time.Hour))
+}
+
+func (w *liveWindow) snapshot(now time.Time) *models.Analytics {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	start := now.Add(-w.window)
+	for w.queue.Len() > 0 && w.queue[0].event.Timestamp.Before(start) {
+		w.forgetLocked(heap.Pop(&w.queue).(*queuedEvent).event)
+	}
+
+	eventsByType := make(map[models.EventType]int, len(w.eventsByType))
+	for eventType, count := range w.eventsByType {
+		eventsByType[eventType] = count
+	}
+
+	return &models.Analytics{
+		TotalEvents:   len(w.members),
+		EventsByType:  eventsByType,
+		UniqueUsers:   len(w.users),
+		EventsPerHour: sortedHours(w.hours),
+	}
+}
+
+func decrement[K comparable](counts map[K]int, key K) {
+	counts[key]--
+	if counts[key] <= 0 {
+		delete(counts, key)
+	}
+}
+
+// queuedEvent is an event in a liveWindow with its place in the queue, so
+// it can be taken out again when it leaves the window early.
+type queuedEvent struct {
+	event *models.Event
+	index int
+}
+
+// eventQueue is a min-heap of events ordered by timestamp.
+type eventQueue []*queuedEvent
+
+func (q eventQueue) Len() int { return len(q) }
+func (q eventQueue) Less(i, j int) bool {
+	return q[i].event.Timestamp.Before(*q[j].event.Timestamp)
+}
+
+func (q eventQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *eventQueue) Push(x any) {
+	queued := x.(*queuedEvent)
+	queued.index = len(*q)
+	*q = append(*q, queued)
+}
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	n := len(old)
+	queued := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return queued
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsService_StreamAnalytics(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewAnalyticsService(store, config.AnalyticsConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	now := time.Now()
+	old := now.Add(-2 * time.Hour)
+	seeded := newTestEvent("123", models.EventTypePageView, now.Add(-time.Minute))
+	require.NoError(t, store.Save(ctx, seeded))
+	require.NoError(t, store.Save(ctx, newTestEvent("456", models.EventTypeClick, old)))
+
+	updates, err := service.StreamAnalytics(ctx, StreamOptions{Window: time.Hour, EveryN: 1})
+	require.NoError(t, err)
+
+	initial := receive(t, updates)
+	assert.Equal(t, 1, initial.TotalEvents)
+	assert.Equal(t, 1, initial.UniqueUsers)
+	assert.Equal(t, map[models.EventType]int{models.EventTypePageView: 1}, initial.EventsByType)
+
+	require.NoError(t, store.Save(ctx, newTestEvent("789", models.EventTypeClick, now)))
+	added := receive(t, updates)
+	assert.Equal(t, 2, added.TotalEvents)
+	assert.Equal(t, 2, added.UniqueUsers)
+	assert.Equal(t, 1, added.EventsByType[models.EventTypeClick])
+
+	require.NoError(t, store.Delete(ctx, seeded.EventID))
+	require.NoError(t, store.Save(ctx, newTestEvent("789", models.EventTypeClick, now)))
+	removed := receive(t, updates)
+	assert.Equal(t, 2, removed.TotalEvents)
+	assert.Equal(t, 1, removed.UniqueUsers)
+	assert.Equal(t, map[models.EventType]int{models.EventTypeClick: 2}, removed.EventsByType)
+
+	cancel()
+	for range updates {
+	}
+}
+
+func TestAnalyticsService_StreamAnalyticsValidation(t *testing.T) {
+	service := NewAnalyticsService(storage.NewEventStorage(), config.AnalyticsConfig{})
+
+	tests := []struct {
+		name          string
+		opts          StreamOptions
+		expectedError string
+	}{
+		{
+			name:          "missing window",
+			opts:          StreamOptions{Interval: time.Second},
+			expectedError: "window must be greater than 0",
+		},
+		{
+			name:          "no trigger",
+			opts:          StreamOptions{Window: time.Hour},
+			expectedError: "interval or every must be greater than 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.StreamAnalytics(context.Background(), tt.opts)
+			require.Error(t, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
+
+func TestLiveWindow_Eviction(t *testing.T) {
+	window := newLiveWindow(time.Hour, 0)
+	now := time.Now()
+
+	window.add(newTestEvent("123", models.EventTypePageView, now.Add(-30*time.Minute)))
+	window.add(newTestEvent("456", models.EventTypePageView, now))
+
+	assert.Equal(t, 2, window.snapshot(now).TotalEvents)
+
+	later := window.snapshot(now.Add(45 * time.Minute))
+	assert.Equal(t, 1, later.TotalEvents)
+	assert.Equal(t, 1, later.UniqueUsers)
+}
+
+func TestLiveWindow_RemoveFromQueue(t *testing.T) {
+	window := newLiveWindow(time.Hour, 0)
+	now := time.Now()
+	deleted := newTestEvent("123", models.EventTypePageView, now.Add(-30*time.Minute))
+	replaced := newTestEvent("456", models.EventTypeClick, now.Add(-20*time.Minute))
+	window.add(deleted)
+	window.add(replaced)
+	window.add(newTestEvent("789", models.EventTypePageView, now))
+
+	// Deleted and replaced events leave the queue straight away rather than
+	// waiting there until they expire.
+	window.remove(deleted)
+	replacement := *replaced
+	replacement.EventType = models.EventTypePurchase
+	window.add(&replacement)
+	assert.Len(t, window.queue, 2)
+	assert.Len(t, window.members, 2)
+
+	snapshot := window.snapshot(now)
+	assert.Equal(t, 2, snapshot.TotalEvents)
+	assert.Equal(t, map[models.EventType]int{models.EventTypePurchase: 1, models.EventTypePageView: 1}, snapshot.EventsByType)
+	// Eviction still finds the oldest event at the front of the queue.
+	later := window.snapshot(now.Add(45 * time.Minute))
+	assert.Equal(t, 1, later.TotalEvents)
+	assert.Len(t, window.queue, 1)
+}
+
+func newTestEvent(userID string, eventType models.EventType, timestamp time.Time) *models.Event {
+	return &models.Event{
+		EventID:   uuid.New().String(),
+		UserID:    userID,
+		EventType: eventType,
+		Timestamp: &timestamp,
+	}
+}
+
+func receive(t *testing.T, updates <-chan *models.Analytics) *models.Analytics {
+	t.Helper()
+	select {
+	case analytics, ok := <-updates:
+		require.True(t, ok)
+		return analytics
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for analytics update")
+		return nil
+	}
+}
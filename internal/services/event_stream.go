@@ -0,0 +1,59 @@
+package services
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// eventStreamBuffer is how many events a subscriber may fall behind by
+// before it starts missing them.
+const eventStreamBuffer = 256
+
+// EventStream is an EventPublisher that fans stored events out to live
+// subscribers, like WebSocket connections and gRPC streams, whichever API
+// the events arrived through. A subscriber that falls behind misses events
+// rather than blocking ingest.
+type EventStream struct {
+	mutex       sync.RWMutex
+	subscribers map[chan *models.Event]bool
+}
+
+func NewEventStream() *EventStream {
+	return &EventStream{
+		subscribers: make(map[chan *models.Event]bool),
+	}
+}
+
+func (s *EventStream) Publish(events ...*models.Event) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for subscriber := range s.subscribers {
+		for _, event := range events {
+			select {
+			case subscriber <- event:
+			default:
+				slog.Warn("dropping event for slow subscriber", "event_id", event.EventID)
+			}
+		}
+	}
+}
+
+// Subscribe returns a channel of events and a function to unsubscribe,
+// which closes the channel.
+func (s *EventStream) Subscribe() (<-chan *models.Event, func()) {
+	subscriber := make(chan *models.Event, eventStreamBuffer)
+	s.mutex.Lock()
+	s.subscribers[subscriber] = true
+	s.mutex.Unlock()
+
+	return subscriber, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if s.subscribers[subscriber] {
+			delete(s.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
@@ -2,27 +2,107 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/dnakolan/event-processing-service/internal/models"
 	"github.com/dnakolan/event-processing-service/internal/storage"
 )
 
 type EventsService interface {
+	// IngestEvent is the path for events arriving from outside the service.
+	// It validates req, enriches the event with the time it was received and,
+	// when it has none, country, and stores it. An event whose ID is already
+	// stored is a duplicate: the stored one is returned with created false.
+	IngestEvent(ctx context.Context, req *models.CreateEventRequest, country string) (event *models.Event, created bool, err error)
+	// ImportEvent is IngestEvent for backfills: the event is stored as sent,
+	// keeping the client's timestamp.
+	ImportEvent(ctx context.Context, event *models.Event) (created bool, err error)
 	CreateEvent(ctx context.Context, event *models.Event) error
 	GetEvent(ctx context.Context, id string) (*models.Event, error)
 	GetEvents(ctx context.Context, filter *models.EventFilter) ([]*models.Event, error)
+	// ScanEvents calls fn with each event matching filter as storage reads
+	// them, a page at a time, for results too large to hold at once. Users
+	// come in ID order and each user's events in time order.
+	ScanEvents(ctx context.Context, filter *models.EventFilter, fn func(*models.Event) error) error
+}
+
+// EventPublisher receives each event once it has been stored. Publish must
+// not block ingest, so implementations hand events off asynchronously.
+type EventPublisher interface {
+	Publish(events ...*models.Event)
+}
+
+// ValidationError is returned by IngestEvent for an event that can never be
+// stored, as opposed to a failure worth retrying.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
 }
 
 type eventsService struct {
-	storage storage.EventStorage
+	storage    storage.EventStorage
+	publishers []EventPublisher
 }
 
-func NewEventsService(storage storage.EventStorage) *eventsService {
-	return &eventsService{storage: storage}
+func NewEventsService(storage storage.EventStorage, publishers ...EventPublisher) *eventsService {
+	return &eventsService{storage: storage, publishers: publishers}
+}
+
+func (s *eventsService) IngestEvent(ctx context.Context, req *models.CreateEventRequest, country string) (*models.Event, bool, error) {
+	if err := req.Validate(); err != nil {
+		return nil, false, &ValidationError{Err: err}
+	}
+
+	now := time.Now()
+	event := req.NewEventFromRequest()
+	event.Timestamp = &now
+	if event.Properties.Country == "" {
+		event.Properties.Country = country
+	}
+	stored, created, err := s.storage.SaveIfAbsent(ctx, event)
+	if err != nil {
+		return nil, false, err
+	}
+	if created {
+		s.publish(stored)
+	}
+	return stored, created, nil
+}
+
+func (s *eventsService) ImportEvent(ctx context.Context, event *models.Event) (bool, error) {
+	req := models.CreateEventRequest{Event: *event}
+	if err := req.Validate(); err != nil {
+		return false, &ValidationError{Err: err}
+	}
+	_, created, err := s.storage.SaveIfAbsent(ctx, event)
+	if err != nil {
+		return false, err
+	}
+	if created {
+		s.publish(event)
+	}
+	return created, nil
 }
 
 func (s *eventsService) CreateEvent(ctx context.Context, event *models.Event) error {
-	return s.storage.Save(ctx, event)
+	if err := s.storage.Save(ctx, event); err != nil {
+		return err
+	}
+	s.publish(event)
+	return nil
+}
+
+func (s *eventsService) publish(event *models.Event) {
+	for _, publisher := range s.publishers {
+		publisher.Publish(event)
+	}
 }
 
 func (s *eventsService) GetEvent(ctx context.Context, id string) (*models.Event, error) {
@@ -32,3 +112,7 @@ func (s *eventsService) GetEvent(ctx context.Context, id string) (*models.Event,
 func (s *eventsService) GetEvents(ctx context.Context, filter *models.EventFilter) ([]*models.Event, error) {
 	return s.storage.FindAll(ctx, filter)
 }
+
+func (s *eventsService) ScanEvents(ctx context.Context, filter *models.EventFilter, fn func(*models.Event) error) error {
+	return s.storage.Scan(ctx, filter, fn)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingPublisher struct {
+	mutex  sync.Mutex
+	events []*models.Event
+}
+
+func (p *recordingPublisher) Publish(events ...*models.Event) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.events = append(p.events, events...)
+}
+
+func TestEventsService_IngestEvent(t *testing.T) {
+	store := storage.NewEventStorage()
+	publisher := &recordingPublisher{}
+	service := NewEventsService(store, publisher)
+	ctx := context.Background()
+	sent := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	req := &models.CreateEventRequest{Event: *newTestEvent("alice", models.EventTypePageView, sent)}
+	req.Properties.Page = "/home"
+	event, created, err := service.IngestEvent(ctx, req, "GB")
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.True(t, event.Timestamp.After(sent), "stamped with the time it was received")
+	assert.Equal(t, "GB", event.Properties.Country)
+
+	duplicate := &models.CreateEventRequest{Event: *newTestEvent("bob", models.EventTypePageView, sent)}
+	duplicate.EventID = event.EventID
+	duplicate.Properties.Page = "/pricing"
+	stored, created, err := service.IngestEvent(ctx, duplicate, "")
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "alice", stored.UserID)
+	assert.Len(t, publisher.events, 1)
+
+	invalid := &models.CreateEventRequest{Event: *newTestEvent("carol", models.EventTypePageView, sent)}
+	_, _, err = service.IngestEvent(ctx, invalid, "")
+	var validation *ValidationError
+	assert.True(t, errors.As(err, &validation))
+	assert.EqualError(t, err, "page is required for page_view events")
+}
+
+func TestEventsService_IngestEvent_Concurrent(t *testing.T) {
+	store := storage.NewEventStorage()
+	publisher := &recordingPublisher{}
+	service := NewEventsService(store, publisher)
+	ctx := context.Background()
+	sent := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	template := newTestEvent("alice", models.EventTypeSignup, sent)
+	template.Properties.Email = "alice@example.com"
+
+	// A redelivery racing a retry of the same event is stored once.
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	created := 0
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := &models.CreateEventRequest{Event: *template}
+			_, ok, err := service.IngestEvent(ctx, req, "")
+			assert.NoError(t, err)
+			if ok {
+				mutex.Lock()
+				created++
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, created)
+	assert.Len(t, publisher.events, 1)
+}
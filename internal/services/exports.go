@@ -0,0 +1,368 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+)
+
+const (
+	// parquetRowGroupSize bounds the rows buffered before a Parquet row group
+	// is written out, and so the memory an export holds.
+	parquetRowGroupSize = 10000
+
+	// maxExportJobs caps the jobs remembered, finished ones dropped oldest
+	// first. Their files are left in place.
+	maxExportJobs = 100
+)
+
+var ErrExportNotFound = errors.New("export job not found")
+
+type ExportsService interface {
+	// Export writes every event matching filter to w in format, straight
+	// from storage, and returns how many it wrote.
+	Export(ctx context.Context, filter *models.EventFilter, format models.ExportFormat, w io.Writer) (int, error)
+	// CreateExportJob starts an export to a file in the exports directory,
+	// for exports too large for one request.
+	CreateExportJob(ctx context.Context, filter *models.EventFilter, format models.ExportFormat) (*models.ExportJob, error)
+	ListExportJobs(ctx context.Context) []models.ExportJob
+	GetExportJob(ctx context.Context, id string) (*models.ExportJob, error)
+}
+
+type exportsService struct {
+	storage storage.EventStorage
+	config  config.ExportsConfig
+
+	mutex sync.Mutex
+	jobs  map[string]*models.ExportJob
+	order []string
+}
+
+func NewExportsService(storage storage.EventStorage, config config.ExportsConfig) ExportsService {
+	return &exportsService{
+		storage: storage,
+		config:  config,
+		jobs:    make(map[string]*models.ExportJob),
+	}
+}
+
+func (s *exportsService) Export(ctx context.Context, filter *models.EventFilter, format models.ExportFormat, w io.Writer) (int, error) {
+	if err := validateExport(filter, format); err != nil {
+		return 0, err
+	}
+	return s.export(ctx, filter, format, w)
+}
+
+func validateExport(filter *models.EventFilter, format models.ExportFormat) error {
+	if err := format.Validate(); err != nil {
+		return &ValidationError{Err: err}
+	}
+	if err := filter.Validate(); err != nil {
+		return &ValidationError{Err: err}
+	}
+	return nil
+}
+
+func (s *exportsService) export(ctx context.Context, filter *models.EventFilter, format models.ExportFormat, w io.Writer) (int, error) {
+	writer := newExportWriter(format, w)
+	count := 0
+	err := s.storage.Scan(ctx, filter, func(event *models.Event) error {
+		count++
+		return writer.Write(event)
+	})
+	if err != nil {
+		return count, err
+	}
+	return count, writer.Close()
+}
+
+func (s *exportsService) CreateExportJob(_ context.Context, filter *models.EventFilter, format models.ExportFormat) (*models.ExportJob, error) {
+	if err := validateExport(filter, format); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.config.Directory, 0o755); err != nil {
+		return nil, err
+	}
+
+	job := &models.ExportJob{
+		ID:        uuid.New().String(),
+		Format:    format,
+		Filter:    *filter,
+		Status:    models.ExportStatusRunning,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.mutex.Lock()
+	s.jobs[job.ID] = job
+	s.order = append(s.order, job.ID)
+	s.evict()
+	created := *job
+	s.mutex.Unlock()
+
+	go s.run(job.ID, filter, format)
+	return &created, nil
+}
+
+// run writes a job's export to a temporary file, renamed into place once it
+// is complete so a partial export is never mistaken for a whole one.
+func (s *exportsService) run(id string, filter *models.EventFilter, format models.ExportFormat) {
+	path := filepath.Join(s.config.Directory, "events-"+id+format.Extension())
+	count, err := s.exportFile(path, filter, format)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	job.CompletedAt = &now
+	job.Events = count
+	if err != nil {
+		slog.Error("export failed", "job", id, "error", err.Error())
+		job.Status = models.ExportStatusFailed
+		job.Error = err.Error()
+		return
+	}
+	job.Status = models.ExportStatusCompleted
+	job.Path = path
+}
+
+func (s *exportsService) exportFile(path string, filter *models.EventFilter, format models.ExportFormat) (int, error) {
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	buffered := bufio.NewWriterSize(file, 64*1024)
+	count, err := s.export(context.Background(), filter, format, buffered)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return count, err
+	}
+	return count, nil
+}
+
+// evict drops the oldest finished jobs beyond maxExportJobs. Callers hold
+// s.mutex.
+func (s *exportsService) evict() {
+	for i := 0; len(s.jobs) > maxExportJobs && i < len(s.order); {
+		if s.jobs[s.order[i]].Status == models.ExportStatusRunning {
+			i++
+			continue
+		}
+		delete(s.jobs, s.order[i])
+		s.order = append(s.order[:i], s.order[i+1:]...)
+	}
+}
+
+func (s *exportsService) ListExportJobs(_ context.Context) []models.ExportJob {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	jobs := make([]models.ExportJob, 0, len(s.order))
+	for _, id := range s.order {
+		jobs = append(jobs, *s.jobs[id])
+	}
+	return jobs
+}
+
+func (s *exportsService) GetExportJob(_ context.Context, id string) (*models.ExportJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrExportNotFound
+	}
+	found := *job
+	return &found, nil
+}
+
+// exportWriter encodes events one at a time. Close writes out anything still
+// buffered, but leaves the underlying writer open.
+type exportWriter interface {
+	Write(event *models.Event) error
+	Close() error
+}
+
+func newExportWriter(format models.ExportFormat, w io.Writer) exportWriter {
+	switch format {
+	case models.ExportFormatCSV:
+		return newCSVExportWriter(w)
+	case models.ExportFormatParquet:
+		return newParquetExportWriter(w)
+	default:
+		return &ndjsonExportWriter{encoder: json.NewEncoder(w)}
+	}
+}
+
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonExportWriter) Write(event *models.Event) error {
+	return w.encoder.Encode(event)
+}
+
+func (w *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// exportColumns are the CSV export's columns, in order. They are the columns
+// a CSV import reads, so an export can be imported again.
+var exportColumns = []string{
+	"event_id", "user_id", "event_type", "timestamp",
+	"page", "amount", "product_id", "email", "link", "country", "currency",
+	"name",
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+	record []string
+	header bool
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	return &csvExportWriter{writer: csv.NewWriter(w), record: make([]string, len(exportColumns))}
+}
+
+func (w *csvExportWriter) Write(event *models.Event) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	amount := ""
+	if event.Properties.Amount != 0 {
+		amount = strconv.FormatFloat(event.Properties.Amount, 'f', -1, 64)
+	}
+	w.record[0] = event.EventID
+	w.record[1] = event.UserID
+	w.record[2] = string(event.EventType)
+	w.record[3] = ""
+	if event.Timestamp != nil {
+		w.record[3] = event.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+	w.record[4] = event.Properties.Page
+	w.record[5] = amount
+	w.record[6] = event.Properties.ProductID
+	w.record[7] = event.Properties.Email
+	w.record[8] = event.Properties.Link
+	w.record[9] = event.Properties.Country
+	w.record[10] = event.Properties.Currency
+	w.record[11] = event.Properties.Name
+	return w.writer.Write(w.record)
+}
+
+// writeHeader writes the header before the first event, or on Close when
+// there were none, so an empty export is still a valid file.
+func (w *csvExportWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+	return w.writer.Write(exportColumns)
+}
+
+func (w *csvExportWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// parquetEvent is an event flattened into Parquet columns. Properties an
+// event doesn't have are null, as is the timestamp of an event without one;
+// timestamps keep the nanosecond precision of the other formats.
+type parquetEvent struct {
+	EventID   string     `parquet:"event_id"`
+	UserID    string     `parquet:"user_id"`
+	EventType string     `parquet:"event_type"`
+	Timestamp *time.Time `parquet:"timestamp,optional"`
+	Page      string     `parquet:"page,optional"`
+	Amount    float64    `parquet:"amount,optional"`
+	ProductID string     `parquet:"product_id,optional"`
+	Email     string     `parquet:"email,optional"`
+	Link      string     `parquet:"link,optional"`
+	Country   string     `parquet:"country,optional"`
+	Currency  string     `parquet:"currency,optional"`
+	Name      string     `parquet:"name,optional"`
+}
+
+type parquetExportWriter struct {
+	writer *parquet.GenericWriter[parquetEvent]
+	rows   []parquetEvent
+}
+
+func newParquetExportWriter(w io.Writer) *parquetExportWriter {
+	return &parquetExportWriter{
+		writer: parquet.NewGenericWriter[parquetEvent](w,
+			parquet.Compression(&parquet.Snappy),
+			parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+		),
+		rows: make([]parquetEvent, 0, 1024),
+	}
+}
+
+func (w *parquetExportWriter) Write(event *models.Event) error {
+	var timestamp *time.Time
+	if event.Timestamp != nil {
+		utc := event.Timestamp.UTC()
+		timestamp = &utc
+	}
+	w.rows = append(w.rows, parquetEvent{
+		EventID:   event.EventID,
+		UserID:    event.UserID,
+		EventType: string(event.EventType),
+		Timestamp: timestamp,
+		Page:      event.Properties.Page,
+		Amount:    event.Properties.Amount,
+		ProductID: event.Properties.ProductID,
+		Email:     event.Properties.Email,
+		Link:      event.Properties.Link,
+		Country:   event.Properties.Country,
+		Currency:  event.Properties.Currency,
+		Name:      event.Properties.Name,
+	})
+	if len(w.rows) < cap(w.rows) {
+		return nil
+	}
+	return w.flushRows()
+}
+
+func (w *parquetExportWriter) flushRows() error {
+	if _, err := w.writer.Write(w.rows); err != nil {
+		return fmt.Errorf("writing parquet: %w", err)
+	}
+	w.rows = w.rows[:0]
+	return nil
+}
+
+func (w *parquetExportWriter) Close() error {
+	if err := w.flushRows(); err != nil {
+		return err
+	}
+	return w.writer.Close()
+}
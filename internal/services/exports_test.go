@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTestStorage(t *testing.T) storage.EventStorage {
+	t.Helper()
+	store := storage.NewEventStorage()
+	at := func(hour int) *time.Time {
+		timestamp := time.Date(2024, 1, 2, hour, 0, 0, 0, time.UTC)
+		return &timestamp
+	}
+	for _, event := range []*models.Event{
+		{EventID: "3", UserID: "bob", EventType: models.EventTypeClick, Timestamp: at(1), Properties: models.EventProperties{Link: "/buy"}},
+		{EventID: "2", UserID: "alice", EventType: models.EventTypePurchase, Timestamp: at(2), Properties: models.EventProperties{Amount: 9.99, ProductID: "xyz", Currency: "EUR"}},
+		{EventID: "1", UserID: "alice", EventType: models.EventTypePageView, Timestamp: at(1), Properties: models.EventProperties{Page: "/home, again", Country: "GB"}},
+	} {
+		require.NoError(t, store.Save(context.Background(), event))
+	}
+	return store
+}
+
+func TestExportsService_NDJSON(t *testing.T) {
+	service := NewExportsService(exportTestStorage(t), config.ExportsConfig{})
+
+	var out bytes.Buffer
+	eventType := models.EventTypePurchase
+	count, err := service.Export(context.Background(), &models.EventFilter{EventType: &eventType}, models.ExportFormatNDJSON, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, `{"event_id":"2","user_id":"alice","event_type":"purchase","timestamp":"2024-01-02T02:00:00Z","properties":{"page":"","amount":9.99,"product_id":"xyz","email":"","link":"","currency":"EUR"}}`+"\n", out.String())
+
+	_, err = service.Export(context.Background(), &models.EventFilter{}, "xml", &out)
+	var invalid *ValidationError
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestExportsService_CSVRoundTrip(t *testing.T) {
+	service := NewExportsService(exportTestStorage(t), config.ExportsConfig{})
+
+	var out bytes.Buffer
+	count, err := service.Export(context.Background(), &models.EventFilter{}, models.ExportFormatCSV, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, strings.Join([]string{
+		"event_id,user_id,event_type,timestamp,page,amount,product_id,email,link,country,currency,name",
+		`1,alice,page_view,2024-01-02T01:00:00Z,"/home, again",,,,,GB,,`,
+		"2,alice,purchase,2024-01-02T02:00:00Z,,9.99,xyz,,,,EUR,",
+		"3,bob,click,2024-01-02T01:00:00Z,,,,,/buy,,,",
+	}, "\n")+"\n", out.String())
+
+	imported := storage.NewEventStorage()
+	job, err := NewImportsService(NewEventsService(imported)).Import(context.Background(), "", models.ImportFormatCSV, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 3, job.Accepted)
+	event, err := imported.FindById(context.Background(), "2")
+	require.NoError(t, err)
+	assert.Equal(t, 9.99, event.Properties.Amount)
+
+	out.Reset()
+	user := "nobody"
+	_, err = service.Export(context.Background(), &models.EventFilter{UserID: &user}, models.ExportFormatCSV, &out)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(exportColumns, ",")+"\n", out.String(), "header even when empty")
+}
+
+func TestExportsService_Parquet(t *testing.T) {
+	service := NewExportsService(exportTestStorage(t), config.ExportsConfig{})
+
+	var out bytes.Buffer
+	count, err := service.Export(context.Background(), &models.EventFilter{}, models.ExportFormatParquet, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	rows, err := parquet.Read[parquetEvent](bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	timestamp := time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, parquetEvent{
+		EventID:   "2",
+		UserID:    "alice",
+		EventType: "purchase",
+		Timestamp: &timestamp,
+		Amount:    9.99,
+		ProductID: "xyz",
+		Currency:  "EUR",
+	}, rows[1])
+
+	file, err := parquet.OpenFile(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	page, ok := file.Schema().Lookup("page")
+	require.True(t, ok)
+	assert.True(t, page.Node.Optional(), "missing properties are null")
+}
+
+func TestExportsService_WithoutTimestamp(t *testing.T) {
+	store := exportTestStorage(t)
+	// Stored as events sent over the WebSocket once were, unvalidated.
+	require.NoError(t, store.Save(context.Background(), &models.Event{EventID: "4", UserID: "carol", EventType: models.EventTypeSignup}))
+	dir := t.TempDir()
+	service := NewExportsService(store, config.ExportsConfig{Directory: dir})
+	ctx := context.Background()
+	user := "carol"
+
+	var out bytes.Buffer
+	count, err := service.Export(ctx, &models.EventFilter{UserID: &user}, models.ExportFormatCSV, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, strings.Join(exportColumns, ",")+"\n4,carol,signup,,,,,,,,,\n", out.String())
+
+	out.Reset()
+	_, err = service.Export(ctx, &models.EventFilter{UserID: &user}, models.ExportFormatParquet, &out)
+	require.NoError(t, err)
+	rows, err := parquet.Read[parquetEvent](bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Nil(t, rows[0].Timestamp)
+
+	// A time range leaves the event out rather than failing the job.
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	job, err := service.CreateExportJob(ctx, &models.EventFilter{StartTimestamp: &start}, models.ExportFormatParquet)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		job, err = service.GetExportJob(ctx, job.ID)
+		return err == nil && job.Status != models.ExportStatusRunning
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, models.ExportStatusCompleted, job.Status)
+	assert.Equal(t, 3, job.Events)
+}
+
+func TestExportsService_Job(t *testing.T) {
+	dir := t.TempDir()
+	service := NewExportsService(exportTestStorage(t), config.ExportsConfig{Directory: dir})
+	ctx := context.Background()
+
+	user := "alice"
+	job, err := service.CreateExportJob(ctx, &models.EventFilter{UserID: &user}, models.ExportFormatNDJSON)
+	require.NoError(t, err)
+	assert.Equal(t, models.ExportStatusRunning, job.Status)
+
+	require.Eventually(t, func() bool {
+		job, err = service.GetExportJob(ctx, job.ID)
+		return err == nil && job.Status != models.ExportStatusRunning
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, models.ExportStatusCompleted, job.Status)
+	assert.Equal(t, 2, job.Events)
+	assert.Equal(t, filepath.Join(dir, "events-"+job.ID+".ndjson"), job.Path)
+
+	data, err := os.ReadFile(job.Path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(data, []byte("\n")))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file left behind")
+
+	assert.Len(t, service.ListExportJobs(ctx), 1)
+	_, err = service.GetExportJob(ctx, "missing")
+	assert.ErrorIs(t, err, ErrExportNotFound)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+func (s *analyticsService) GetFunnel(ctx context.Context, req *models.FunnelRequest) (*models.Funnel, error) {
+	start, end := req.Range(time.Now())
+	conversionWindow, err := models.ParseWindow(req.ConversionWindow)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users must enter the funnel within [start, end] but may complete it up
+	// to one conversion window later.
+	horizon := end.Add(conversionWindow)
+	events, err := s.storage.FindAll(ctx, &models.EventFilter{
+		StartTimestamp: &start,
+		EndTimestamp:   &horizon,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reached := make([]int, len(req.Steps))
+	durations := make([][]float64, len(req.Steps))
+	for _, userEvents := range groupByUser(events) {
+		path := bestFunnelPath(userEvents, req.Steps, start, end, conversionWindow)
+		for i := range path {
+			reached[i]++
+			if i > 0 {
+				durations[i] = append(durations[i], path[i].Sub(path[i-1]).Seconds())
+			}
+		}
+	}
+
+	funnel := &models.Funnel{
+		ConversionWindow: req.ConversionWindow,
+		Start:            start,
+		End:              end,
+		Steps:            make([]models.FunnelStepResult, len(req.Steps)),
+	}
+	for i, step := range req.Steps {
+		result := models.FunnelStepResult{
+			Name:      step.Name,
+			EventType: step.EventType,
+			Users:     reached[i],
+		}
+		if reached[0] > 0 {
+			result.ConversionRate = float64(reached[i]) / float64(reached[0])
+		}
+		if i == 0 {
+			result.StepConversionRate = result.ConversionRate
+		} else if reached[i-1] > 0 {
+			result.StepConversionRate = float64(reached[i]) / float64(reached[i-1])
+		}
+		if len(durations[i]) > 0 {
+			median := median(durations[i])
+			result.MedianSecondsToStep = &median
+		}
+		funnel.Steps[i] = result
+	}
+	return funnel, nil
+}
+
+// bestFunnelPath returns the completion times of the furthest sequence of
+// steps a user reached from any entry in [start, end]. Each step must follow
+// the previous one, and all within window of the entry.
+func bestFunnelPath(events []*models.Event, steps []models.FunnelStep, start, end time.Time, window time.Duration) []time.Time {
+	var best []time.Time
+	for i, entry := range events {
+		if entry.Timestamp.Before(start) || entry.Timestamp.After(end) || !steps[0].Matches(entry) {
+			continue
+		}
+		deadline := entry.Timestamp.Add(window)
+		path := []time.Time{*entry.Timestamp}
+		for _, event := range events[i+1:] {
+			if len(path) == len(steps) || event.Timestamp.After(deadline) {
+				break
+			}
+			if steps[len(path)].Matches(event) {
+				path = append(path, *event.Timestamp)
+			}
+		}
+		if len(path) > len(best) {
+			best = path
+		}
+		if len(best) == len(steps) {
+			break
+		}
+	}
+	return best
+}
+
+// groupByUser splits events per user, each in time order.
+func groupByUser(events []*models.Event) map[string][]*models.Event {
+	byUser := make(map[string][]*models.Event)
+	for _, event := range events {
+		byUser[event.UserID] = append(byUser[event.UserID], event)
+	}
+	for _, userEvents := range byUser {
+		sortByTime(userEvents)
+	}
+	return byUser
+}
+
+func sortByTime(events []*models.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(*events[j].Timestamp)
+	})
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
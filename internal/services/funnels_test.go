@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsService_GetFunnel(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewAnalyticsService(store, config.AnalyticsConfig{})
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	save := func(userID string, eventType models.EventType, offset time.Duration, page string) {
+		event := newTestEvent(userID, eventType, t0.Add(offset))
+		event.Properties.Page = page
+		require.NoError(t, store.Save(ctx, event))
+	}
+
+	// Completes every step.
+	save("u1", models.EventTypePageView, 0, "/pricing")
+	save("u1", models.EventTypeSignup, time.Hour, "")
+	save("u1", models.EventTypePurchase, 2*time.Hour, "")
+	// Purchases after the conversion window.
+	save("u2", models.EventTypePageView, 0, "/pricing")
+	save("u2", models.EventTypeSignup, 30*time.Minute, "")
+	save("u2", models.EventTypePurchase, 30*time.Hour, "")
+	// Never views pricing.
+	save("u3", models.EventTypePageView, 0, "/home")
+	save("u3", models.EventTypeSignup, time.Hour, "")
+	// Steps out of order.
+	save("u4", models.EventTypeSignup, 0, "")
+	save("u4", models.EventTypePageView, time.Hour, "/pricing")
+	// Only the second visit to pricing leads anywhere.
+	save("u5", models.EventTypePageView, 0, "/pricing")
+	save("u5", models.EventTypePageView, 48*time.Hour, "/pricing")
+	save("u5", models.EventTypeSignup, 49*time.Hour, "")
+
+	start := t0.Add(-time.Hour)
+	end := t0.Add(72 * time.Hour)
+	req := &models.FunnelRequest{
+		Steps: []models.FunnelStep{
+			{
+				Name:      "viewed pricing",
+				EventType: models.EventTypePageView,
+				Properties: []models.PropertyPredicate{
+					{Field: "page", Op: models.PredicateOpEq, Value: "/pricing"},
+				},
+			},
+			{Name: "signed up", EventType: models.EventTypeSignup},
+			{Name: "purchased", EventType: models.EventTypePurchase},
+		},
+		ConversionWindow: "24h",
+		Start:            &start,
+		End:              &end,
+	}
+	require.NoError(t, req.Validate())
+
+	funnel, err := service.GetFunnel(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, funnel.Steps, 3)
+
+	assert.Equal(t, 4, funnel.Steps[0].Users)
+	assert.Equal(t, 3, funnel.Steps[1].Users)
+	assert.Equal(t, 1, funnel.Steps[2].Users)
+
+	assert.Equal(t, 1.0, funnel.Steps[0].ConversionRate)
+	assert.Equal(t, 0.75, funnel.Steps[1].ConversionRate)
+	assert.Equal(t, 0.25, funnel.Steps[2].ConversionRate)
+	assert.InDelta(t, 1.0/3, funnel.Steps[2].StepConversionRate, 1e-9)
+
+	assert.Nil(t, funnel.Steps[0].MedianSecondsToStep)
+	assert.Equal(t, 3600.0, *funnel.Steps[1].MedianSecondsToStep)
+	assert.Equal(t, 3600.0, *funnel.Steps[2].MedianSecondsToStep)
+}
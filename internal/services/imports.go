@@ -0,0 +1,420 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxImportLine bounds a single NDJSON line, so one bad line can't
+	// exhaust memory.
+	maxImportLine = 1 << 20
+
+	// maxImportJobs caps the jobs remembered, finished ones dropped oldest
+	// first.
+	maxImportJobs = 100
+)
+
+var (
+	ErrImportNotFound = errors.New("import job not found")
+	ErrImportRunning  = errors.New("import job is already running")
+)
+
+type ImportsService interface {
+	// Import streams the events in body into a job, starting a new one when
+	// jobID is empty and resuming it otherwise. Body is read one record at a
+	// time, never whole. If body breaks off the job is left interrupted and
+	// returned without an error; it can be resumed with the records after
+	// the last one counted.
+	Import(ctx context.Context, jobID string, format models.ImportFormat, body io.Reader) (*models.ImportJob, error)
+	GetImport(ctx context.Context, id string) (*models.ImportJob, error)
+	GetImportErrors(ctx context.Context, id string) ([]models.ImportError, error)
+}
+
+type importsService struct {
+	events EventsService
+
+	mutex sync.Mutex
+	jobs  map[string]*importJob
+	order []string
+}
+
+type importJob struct {
+	mutex  sync.Mutex
+	job    models.ImportJob
+	errors []models.ImportError
+}
+
+func NewImportsService(events EventsService) ImportsService {
+	return &importsService{
+		events: events,
+		jobs:   make(map[string]*importJob),
+	}
+}
+
+func (s *importsService) Import(ctx context.Context, jobID string, format models.ImportFormat, body io.Reader) (*models.ImportJob, error) {
+	if err := format.Validate(); err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+	j, err := s.start(jobID, format)
+	if err != nil {
+		return nil, err
+	}
+
+	var reader importReader
+	if format == models.ImportFormatCSV {
+		reader, err = newCSVImportReader(body)
+	} else {
+		reader = newNDJSONImportReader(body)
+	}
+	if err == nil {
+		err = s.read(ctx, j, reader)
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.job.UpdatedAt = time.Now().UTC()
+	var invalid *ValidationError
+	switch {
+	case err == nil:
+		j.job.Status = models.ImportStatusCompleted
+	case errors.As(err, &invalid):
+		j.job.Status = models.ImportStatusInterrupted
+		return nil, err
+	case errors.Is(err, errImportBody):
+		slog.Warn("import upload broke off", "job", j.job.ID, "records", j.job.Records, "error", err.Error())
+		j.job.Status = models.ImportStatusInterrupted
+	default:
+		j.job.Status = models.ImportStatusInterrupted
+		return nil, err
+	}
+	job := j.job
+	return &job, nil
+}
+
+// start creates a job, or marks an existing one running again.
+func (s *importsService) start(jobID string, format models.ImportFormat) (*importJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	now := time.Now().UTC()
+	if jobID == "" {
+		j := &importJob{job: models.ImportJob{
+			ID:        uuid.New().String(),
+			Format:    format,
+			Status:    models.ImportStatusRunning,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}}
+		s.jobs[j.job.ID] = j
+		s.order = append(s.order, j.job.ID)
+		s.evict()
+		return j, nil
+	}
+
+	j, ok := s.jobs[jobID]
+	if !ok {
+		return nil, ErrImportNotFound
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.job.Status == models.ImportStatusRunning {
+		return nil, ErrImportRunning
+	}
+	if j.job.Format != format {
+		return nil, &ValidationError{Err: fmt.Errorf("job was started as %s", j.job.Format)}
+	}
+	j.job.Status = models.ImportStatusRunning
+	j.job.UpdatedAt = now
+	return j, nil
+}
+
+// evict drops the oldest finished jobs beyond maxImportJobs. Callers hold
+// s.mutex.
+func (s *importsService) evict() {
+	for i := 0; len(s.jobs) > maxImportJobs && i < len(s.order); {
+		j := s.jobs[s.order[i]]
+		j.mutex.Lock()
+		running := j.job.Status == models.ImportStatusRunning
+		j.mutex.Unlock()
+		if running {
+			i++
+			continue
+		}
+		delete(s.jobs, s.order[i])
+		s.order = append(s.order[:i], s.order[i+1:]...)
+	}
+}
+
+func (s *importsService) read(ctx context.Context, j *importJob, reader importReader) error {
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		var invalid *importRecordError
+		if err != nil && !errors.As(err, &invalid) {
+			return err
+		}
+
+		var created bool
+		if err == nil {
+			created, err = s.events.ImportEvent(ctx, event)
+			var validation *ValidationError
+			if err != nil && !errors.As(err, &validation) {
+				return err
+			}
+		}
+
+		j.mutex.Lock()
+		j.job.Records++
+		switch {
+		case err != nil:
+			j.job.Rejected++
+			if len(j.errors) < models.MaxImportErrors {
+				importErr := models.ImportError{Record: j.job.Records, Error: err.Error()}
+				if event != nil {
+					importErr.EventID = event.EventID
+				} else if invalid != nil {
+					importErr.EventID = invalid.eventID
+				}
+				j.errors = append(j.errors, importErr)
+			} else {
+				j.job.ErrorsTruncated = true
+			}
+		case created:
+			j.job.Accepted++
+		default:
+			j.job.Duplicates++
+		}
+		j.mutex.Unlock()
+	}
+}
+
+func (s *importsService) GetImport(_ context.Context, id string) (*models.ImportJob, error) {
+	j, err := s.job(id)
+	if err != nil {
+		return nil, err
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	job := j.job
+	return &job, nil
+}
+
+func (s *importsService) GetImportErrors(_ context.Context, id string) ([]models.ImportError, error) {
+	j, err := s.job(id)
+	if err != nil {
+		return nil, err
+	}
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return append([]models.ImportError(nil), j.errors...), nil
+}
+
+func (s *importsService) job(id string) (*importJob, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrImportNotFound
+	}
+	return j, nil
+}
+
+// errImportBody wraps failures to read the upload itself, as opposed to the
+// records in it.
+var errImportBody = errors.New("reading import body")
+
+// importRecordError is a record that could not be parsed into an event.
+type importRecordError struct {
+	eventID string
+	err     error
+}
+
+func (e *importRecordError) Error() string {
+	return e.err.Error()
+}
+
+// importReader yields the events in an upload one record at a time. Next
+// returns an *importRecordError for a record it can't parse, and io.EOF once
+// the body has ended cleanly.
+type importReader interface {
+	Next() (*models.Event, error)
+}
+
+type ndjsonImportReader struct {
+	reader *bufio.Reader
+}
+
+func newNDJSONImportReader(body io.Reader) *ndjsonImportReader {
+	return &ndjsonImportReader{reader: bufio.NewReaderSize(body, 64*1024)}
+}
+
+func (r *ndjsonImportReader) Next() (*models.Event, error) {
+	for {
+		line, tooLong, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if tooLong {
+			return nil, &importRecordError{err: fmt.Errorf("line is longer than %d bytes", maxImportLine)}
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var event models.Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			return nil, &importRecordError{err: err}
+		}
+		return &event, nil
+	}
+}
+
+// readLine returns the next line, keeping at most maxImportLine bytes of it.
+// A final line without a newline counts only if the body ended cleanly, so a
+// line cut short by a broken upload is never imported.
+func (r *ndjsonImportReader) readLine() ([]byte, bool, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := r.reader.ReadSlice('\n')
+		if len(line)+len(chunk) > maxImportLine {
+			tooLong = true
+		} else {
+			line = append(line, chunk...)
+		}
+		switch {
+		case err == nil:
+			return line, tooLong, nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err == io.EOF:
+			if len(line) == 0 && !tooLong {
+				return nil, false, io.EOF
+			}
+			return line, tooLong, nil
+		default:
+			return nil, false, fmt.Errorf("%w: %w", errImportBody, err)
+		}
+	}
+}
+
+// csvColumns are the columns a CSV import may have, by header name. Property
+// columns may also be prefixed with "properties.".
+var csvColumns = map[string]func(event *models.Event, value string) error{
+	"event_id":   func(e *models.Event, v string) error { e.EventID = v; return nil },
+	"user_id":    func(e *models.Event, v string) error { e.UserID = v; return nil },
+	"event_type": func(e *models.Event, v string) error { e.EventType = models.EventType(v); return nil },
+	"timestamp": func(e *models.Event, v string) error {
+		if v == "" {
+			return nil
+		}
+		timestamp, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return errors.New("timestamp must be an RFC3339 timestamp")
+		}
+		e.Timestamp = &timestamp
+		return nil
+	},
+	"page": func(e *models.Event, v string) error { e.Properties.Page = v; return nil },
+	"amount": func(e *models.Event, v string) error {
+		if v == "" {
+			return nil
+		}
+		amount, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return errors.New("amount must be a number")
+		}
+		e.Properties.Amount = amount
+		return nil
+	},
+	"product_id": func(e *models.Event, v string) error { e.Properties.ProductID = v; return nil },
+	"email":      func(e *models.Event, v string) error { e.Properties.Email = v; return nil },
+	"link":       func(e *models.Event, v string) error { e.Properties.Link = v; return nil },
+	"country":    func(e *models.Event, v string) error { e.Properties.Country = v; return nil },
+	"currency":   func(e *models.Event, v string) error { e.Properties.Currency = v; return nil },
+	"name":       func(e *models.Event, v string) error { e.Properties.Name = v; return nil },
+}
+
+// csvImportReader reads CSV with a header row naming its columns. Every
+// upload, including one resuming a job, starts with the header.
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns []func(event *models.Event, value string) error
+	eventID int
+}
+
+func newCSVImportReader(body io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(body)
+	reader.ReuseRecord = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &csvImportReader{reader: reader}, nil
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, &ValidationError{Err: fmt.Errorf("invalid csv header: %w", err)}
+		}
+		return nil, fmt.Errorf("%w: %w", errImportBody, err)
+	}
+
+	r := &csvImportReader{reader: reader, eventID: -1}
+	for i, name := range header {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "properties.")
+		column, ok := csvColumns[name]
+		if !ok {
+			return nil, &ValidationError{Err: fmt.Errorf("unknown csv column %q", name)}
+		}
+		if name == "event_id" {
+			r.eventID = i
+		}
+		r.columns = append(r.columns, column)
+	}
+	return r, nil
+}
+
+func (r *csvImportReader) Next() (*models.Event, error) {
+	record, err := r.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		var parseErr *csv.ParseError
+		if !errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("%w: %w", errImportBody, err)
+		}
+		recordErr := &importRecordError{err: err}
+		if r.eventID >= 0 && r.eventID < len(record) {
+			recordErr.eventID = record[r.eventID]
+		}
+		return nil, recordErr
+	}
+
+	event := &models.Event{}
+	for i, value := range record {
+		if err := r.columns[i](event, value); err != nil {
+			recordErr := &importRecordError{err: err}
+			if r.eventID >= 0 {
+				recordErr.eventID = record[r.eventID]
+			}
+			return nil, recordErr
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportsService_NDJSON(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewImportsService(NewEventsService(store))
+	ctx := context.Background()
+
+	body := strings.Join([]string{
+		`{"event_id":"1","user_id":"alice","event_type":"page_view","timestamp":"2024-01-02T03:04:05Z","properties":{"page":"/home"}}`,
+		`{"event_id":"2",`,
+		`{"event_id":"3","user_id":"alice","event_type":"page_view","timestamp":"2024-01-02T03:04:05Z"}`,
+		``,
+		`{"event_id":"1","user_id":"alice","event_type":"page_view","timestamp":"2024-01-02T03:04:05Z","properties":{"page":"/home"}}`,
+		`{"event_id":"4","user_id":"bob","event_type":"click","timestamp":"2024-01-03T00:00:00Z","properties":{"link":"/buy"}}`,
+	}, "\n")
+	job, err := service.Import(ctx, "", models.ImportFormatNDJSON, strings.NewReader(body))
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportStatusCompleted, job.Status)
+	assert.Equal(t, 5, job.Records)
+	assert.Equal(t, 2, job.Accepted)
+	assert.Equal(t, 2, job.Rejected)
+	assert.Equal(t, 1, job.Duplicates)
+
+	event, err := store.FindById(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), event.Timestamp.UTC(), "client timestamp kept")
+
+	importErrors, err := service.GetImportErrors(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, importErrors, 2)
+	assert.Equal(t, 2, importErrors[0].Record)
+	assert.Equal(t, models.ImportError{Record: 3, EventID: "3", Error: "page is required for page_view events"}, importErrors[1])
+}
+
+func TestImportsService_CSV(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewImportsService(NewEventsService(store))
+	ctx := context.Background()
+
+	body := "event_id,user_id,event_type,timestamp,properties.amount,product_id\n" +
+		"1,alice,purchase,2024-01-02T03:04:05Z,9.99,xyz\n" +
+		"2,alice,purchase,2024-01-02T03:04:05Z,lots,xyz\n" +
+		"3,alice,purchase\n"
+	job, err := service.Import(ctx, "", models.ImportFormatCSV, strings.NewReader(body))
+	require.NoError(t, err)
+	assert.Equal(t, 3, job.Records)
+	assert.Equal(t, 1, job.Accepted)
+	assert.Equal(t, 2, job.Rejected)
+
+	event, err := store.FindById(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, 9.99, event.Properties.Amount)
+	importErrors, err := service.GetImportErrors(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportError{Record: 2, EventID: "2", Error: "amount must be a number"}, importErrors[0])
+	assert.Equal(t, "3", importErrors[1].EventID)
+
+	_, err = service.Import(ctx, "", models.ImportFormatCSV, strings.NewReader("event_id,colour\n"))
+	var invalid *ValidationError
+	assert.True(t, errors.As(err, &invalid))
+}
+
+// brokenReader returns its data and then fails, like an upload cut off
+// part way.
+type brokenReader struct {
+	data io.Reader
+}
+
+func (r *brokenReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func TestImportsService_Resume(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewImportsService(NewEventsService(store))
+	ctx := context.Background()
+	line := func(id string) string {
+		return `{"event_id":"` + id + `","user_id":"alice","event_type":"signup","timestamp":"2024-01-02T03:04:05Z","properties":{"email":"a@example.com"}}` + "\n"
+	}
+
+	// The upload breaks off half way through the third line.
+	third := line("3")
+	job, err := service.Import(ctx, "", models.ImportFormatNDJSON, &brokenReader{
+		data: strings.NewReader(line("1") + line("2") + third[:len(third)/2]),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportStatusInterrupted, job.Status)
+	assert.Equal(t, 2, job.Records)
+	assert.Equal(t, 0, job.Rejected, "the partial line is not a record")
+
+	job, err = service.Import(ctx, job.ID, models.ImportFormatNDJSON, strings.NewReader(third+line("4")))
+	require.NoError(t, err)
+	assert.Equal(t, models.ImportStatusCompleted, job.Status)
+	assert.Equal(t, 4, job.Records)
+	assert.Equal(t, 4, job.Accepted)
+
+	_, err = service.Import(ctx, job.ID, models.ImportFormatCSV, strings.NewReader(""))
+	var invalid *ValidationError
+	assert.True(t, errors.As(err, &invalid), "resumed in another format")
+	_, err = service.Import(ctx, "missing", models.ImportFormatNDJSON, strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrImportNotFound)
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+func (s *analyticsService) GetRetention(ctx context.Context, query *models.RetentionQuery) (*models.Retention, error) {
+	loc := query.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// A user's first event can predate the range, so everything up to the
+	// last period of the last cohort is needed.
+	horizon := query.Cohort.Truncate(query.End, loc)
+	for i := 0; i <= query.Periods; i++ {
+		horizon = query.Cohort.Next(horizon)
+	}
+	horizon = horizon.Add(-time.Nanosecond)
+	events, err := s.storage.FindAll(ctx, &models.EventFilter{EndTimestamp: &horizon})
+	if err != nil {
+		return nil, err
+	}
+
+	type cohort struct {
+		users    int
+		retained []int
+	}
+	cohorts := make(map[time.Time]*cohort)
+	for _, userEvents := range groupByUser(events) {
+		var first *models.Event
+		for _, event := range userEvents {
+			if query.CohortEvent == "" || event.EventType == query.CohortEvent {
+				first = event
+				break
+			}
+		}
+		if first == nil || first.Timestamp.Before(query.Start) || first.Timestamp.After(query.End) {
+			continue
+		}
+
+		start := query.Cohort.Truncate(*first.Timestamp, loc)
+		c, ok := cohorts[start]
+		if !ok {
+			c = &cohort{retained: make([]int, query.Periods+1)}
+			cohorts[start] = c
+		}
+		c.users++
+
+		returned := make([]bool, query.Periods+1)
+		for _, event := range userEvents {
+			if event.Timestamp.Before(start) {
+				continue
+			}
+			if query.ReturnEvent != "" && event.EventType != query.ReturnEvent {
+				continue
+			}
+			period := query.Cohort.PeriodsBetween(start, *event.Timestamp, loc)
+			if period <= query.Periods {
+				returned[period] = true
+			}
+		}
+		for period, ok := range returned {
+			if ok {
+				c.retained[period]++
+			}
+		}
+	}
+
+	retention := &models.Retention{
+		Cohort:      query.Cohort,
+		CohortEvent: query.CohortEvent,
+		ReturnEvent: query.ReturnEvent,
+		Start:       query.Start,
+		End:         query.End,
+		Cohorts:     make([]models.RetentionCohort, 0),
+	}
+	now := time.Now()
+	for start := query.Cohort.Truncate(query.Start, loc); !start.After(query.End); start = query.Cohort.Next(start) {
+		result := models.RetentionCohort{
+			Start:    start,
+			Retained: make([]int, 0, query.Periods+1),
+			Rates:    make([]float64, 0, query.Periods+1),
+		}
+		c := cohorts[start]
+		if c != nil {
+			result.Users = c.users
+		}
+		periodStart := start
+		for period := 0; period <= query.Periods && periodStart.Before(now); period++ {
+			retained := 0
+			if c != nil {
+				retained = c.retained[period]
+			}
+			result.Retained = append(result.Retained, retained)
+			rate := 0.0
+			if result.Users > 0 {
+				rate = float64(retained) / float64(result.Users)
+			}
+			result.Rates = append(result.Rates, rate)
+			periodStart = query.Cohort.Next(periodStart)
+		}
+		retention.Cohorts = append(retention.Cohorts, result)
+	}
+	return retention, nil
+}
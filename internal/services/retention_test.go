@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsService_GetRetention(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewAnalyticsService(store, config.AnalyticsConfig{})
+	ctx := context.Background()
+	day := func(n int, hour int) time.Time {
+		return time.Date(2025, 6, 1+n, hour, 0, 0, 0, time.UTC)
+	}
+	save := func(userID string, eventType models.EventType, at time.Time) {
+		require.NoError(t, store.Save(ctx, newTestEvent(userID, eventType, at)))
+	}
+
+	// Day 0 cohort.
+	save("u1", models.EventTypeSignup, day(0, 9))
+	save("u1", models.EventTypePageView, day(1, 9))
+	save("u1", models.EventTypePageView, day(2, 23))
+	save("u2", models.EventTypeSignup, day(0, 10))
+	save("u2", models.EventTypePageView, day(2, 1))
+	// Day 1 cohort.
+	save("u3", models.EventTypeSignup, day(1, 12))
+	save("u3", models.EventTypePageView, day(2, 12))
+	// First seen before the range, so in no cohort.
+	save("u4", models.EventTypePageView, day(-3, 12))
+	save("u4", models.EventTypeSignup, day(1, 12))
+	save("u4", models.EventTypePageView, day(2, 12))
+
+	tests := []struct {
+		name     string
+		query    models.RetentionQuery
+		expected []models.RetentionCohort
+	}{
+		{
+			name: "first event",
+			query: models.RetentionQuery{
+				Cohort:  models.GranularityDay,
+				Start:   day(0, 0),
+				End:     day(1, 23),
+				Periods: 2,
+			},
+			expected: []models.RetentionCohort{
+				{Start: day(0, 0), Users: 2, Retained: []int{2, 1, 2}, Rates: []float64{1, 0.5, 1}},
+				{Start: day(1, 0), Users: 1, Retained: []int{1, 1, 0}, Rates: []float64{1, 1, 0}},
+			},
+		},
+		{
+			name: "first signup returning with page views",
+			query: models.RetentionQuery{
+				Cohort:      models.GranularityDay,
+				Start:       day(0, 0),
+				End:         day(1, 23),
+				CohortEvent: models.EventTypeSignup,
+				ReturnEvent: models.EventTypePageView,
+				Periods:     1,
+			},
+			expected: []models.RetentionCohort{
+				{Start: day(0, 0), Users: 2, Retained: []int{0, 1}, Rates: []float64{0, 0.5}},
+				{Start: day(1, 0), Users: 2, Retained: []int{0, 2}, Rates: []float64{0, 1}},
+			},
+		},
+		{
+			name: "weekly",
+			query: models.RetentionQuery{
+				Cohort:  models.GranularityWeek,
+				Start:   day(0, 0),
+				End:     day(1, 23),
+				Periods: 1,
+			},
+			expected: []models.RetentionCohort{
+				// 2025-06-01 is a Sunday, so its week starts on May 26th.
+				{Start: time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC), Users: 2, Retained: []int{2, 2}, Rates: []float64{1, 1}},
+				{Start: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Users: 1, Retained: []int{1, 0}, Rates: []float64{1, 0}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.query.Validate())
+			retention, err := service.GetRetention(ctx, &tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, retention.Cohorts)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/google/uuid"
+)
+
+type SegmentService interface {
+	// Ingest translates Segment messages into events and stores them. A
+	// message that can't be translated or stored is rejected on its own;
+	// only a storage failure fails the call.
+	Ingest(ctx context.Context, messages []models.SegmentMessage, country string) (*models.SegmentResult, error)
+}
+
+type segmentService struct {
+	events EventsService
+	users  UsersService
+	config config.SegmentConfig
+	now    func() time.Time
+}
+
+func NewSegmentService(events EventsService, users UsersService, config config.SegmentConfig) SegmentService {
+	return &segmentService{
+		events: events,
+		users:  users,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+func (s *segmentService) Ingest(ctx context.Context, messages []models.SegmentMessage, country string) (*models.SegmentResult, error) {
+	result := &models.SegmentResult{Success: true}
+	now := s.now().UTC()
+	for i := range messages {
+		message := &messages[i]
+		if err := s.link(ctx, message); err != nil {
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) && !errors.Is(err, ErrAliasConflict) {
+				return nil, err
+			}
+			result.Rejected = append(result.Rejected, models.SegmentRejection{MessageID: message.MessageID, Error: err.Error()})
+			continue
+		}
+		event, err := segmentEvent(message, s.config.TrackEvents, now)
+		if err == nil && event == nil {
+			continue
+		}
+
+		var created bool
+		if err == nil {
+			if event.Properties.Country == "" {
+				event.Properties.Country = country
+			}
+			created, err = s.events.ImportEvent(ctx, event)
+			var invalid *ValidationError
+			if err != nil && !errors.As(err, &invalid) {
+				return nil, err
+			}
+		}
+		switch {
+		case err != nil:
+			result.Rejected = append(result.Rejected, models.SegmentRejection{MessageID: message.MessageID, Error: err.Error()})
+		case created:
+			result.Created++
+		default:
+			result.Duplicates++
+		}
+	}
+	return result, nil
+}
+
+// link applies what identify and alias calls say about who a user is:
+// identify links the anonymousId a visitor had to their userId, and alias
+// links a previousId.
+func (s *segmentService) link(ctx context.Context, message *models.SegmentMessage) error {
+	var previousID string
+	switch message.Type {
+	case models.SegmentTypeIdentify:
+		previousID = message.AnonymousID
+	case models.SegmentTypeAlias:
+		previousID = message.PreviousID
+	default:
+		return nil
+	}
+	if message.UserID == "" || previousID == "" || previousID == message.UserID {
+		return nil
+	}
+	_, err := s.users.AliasUser(ctx, message.UserID, previousID)
+	return err
+}
+
+// segmentEvent translates a Segment message into an event, or returns nil
+// for a call that has no event of its own, like identify and alias:
+//   - page is a page_view of properties.path, or the page in its context.
+//   - track is the event type trackEvents maps its event name to, or the type
+//     it names itself, or else a custom event keeping the name. Purchases take their amount from revenue, total or
+//     value, and their product from product_id, the first of products or
+//     order_id. Clicks take their link from url or href.
+//
+// The event is identified by messageId and belongs to userId, or to
+// anonymousId for a visitor not yet identified. Its time is timestamp, or
+// originalTimestamp corrected for the skew between the client's sentAt
+// and now.
+func segmentEvent(message *models.SegmentMessage, trackEvents map[string]models.EventType, now time.Time) (*models.Event, error) {
+	event := &models.Event{
+		EventID: message.MessageID,
+		UserID:  message.UserID,
+	}
+	if event.EventID == "" {
+		event.EventID = uuid.New().String()
+	}
+	if event.UserID == "" {
+		event.UserID = message.AnonymousID
+	}
+	properties := message.Properties
+
+	switch message.Type {
+	case models.SegmentTypeIdentify, models.SegmentTypeAlias:
+		return nil, nil
+	case models.SegmentTypePage:
+		event.EventType = models.EventTypePageView
+		event.Properties.Page = firstString(properties, "path")
+		if event.Properties.Page == "" {
+			event.Properties.Page = message.Context.Page.Path
+		}
+		if event.Properties.Page == "" {
+			event.Properties.Page = urlPath(firstString(properties, "url"))
+		}
+		if event.Properties.Page == "" {
+			event.Properties.Page = urlPath(message.Context.Page.URL)
+		}
+	case models.SegmentTypeTrack:
+		eventType, ok := trackEvents[message.Event]
+		if !ok {
+			eventType = models.EventType(message.Event)
+		}
+		switch eventType {
+		case models.EventTypePurchase:
+			event.Properties.Amount = firstNumber(properties, "revenue", "total", "value")
+			event.Properties.ProductID = firstString(properties, "product_id")
+			if event.Properties.ProductID == "" {
+				if products, ok := properties["products"].([]any); ok && len(products) > 0 {
+					product, _ := products[0].(map[string]any)
+					event.Properties.ProductID = firstString(product, "product_id")
+				}
+			}
+			if event.Properties.ProductID == "" {
+				event.Properties.ProductID = firstString(properties, "order_id")
+			}
+			event.Properties.Currency = strings.ToUpper(firstString(properties, "currency"))
+		case models.EventTypeClick:
+			event.Properties.Link = firstString(properties, "url", "href", "link")
+		case models.EventTypeSignup, models.EventTypePageView:
+			event.Properties.Page = firstString(properties, "path")
+		case models.EventTypeCustom:
+			event.Properties.Name = message.Event
+		default:
+			eventType = models.EventTypeCustom
+			event.Properties.Name = message.Event
+		}
+		event.EventType = eventType
+	default:
+		return nil, fmt.Errorf("unsupported message type %q", message.Type)
+	}
+
+	event.Properties.Email = firstString(properties, "email")
+	if event.Properties.Email == "" {
+		event.Properties.Email = firstString(message.Context.Traits, "email")
+	}
+
+	switch {
+	case message.Timestamp != nil:
+		event.Timestamp = message.Timestamp
+	case message.OriginalTimestamp != nil && message.SentAt != nil:
+		timestamp := now.Add(message.OriginalTimestamp.Sub(*message.SentAt))
+		event.Timestamp = &timestamp
+	case message.OriginalTimestamp != nil:
+		event.Timestamp = message.OriginalTimestamp
+	default:
+		event.Timestamp = &now
+	}
+	return event, nil
+}
+
+// firstString returns the first of keys that is a non-empty string in
+// properties.
+func firstString(properties map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := properties[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// firstNumber returns the first of keys that is a number, or a string
+// holding one, in properties.
+func firstNumber(properties map[string]any, keys ...string) float64 {
+	for _, key := range keys {
+		switch value := properties[key].(type) {
+		case float64:
+			return value
+		case string:
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				return number
+			}
+		}
+	}
+	return 0
+}
+
+func urlPath(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Path
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var segmentTestConfig = config.SegmentConfig{
+	TrackEvents: map[string]models.EventType{
+		"Order Completed": models.EventTypePurchase,
+		"Signed Up":       models.EventTypeSignup,
+		"Product Clicked": models.EventTypeClick,
+		"Link Clicked":    models.EventTypeClick,
+	},
+}
+
+// TestSegmentEvent_Fixtures checks each Segment message in
+// testdata/segment translates to its event, or fails with its error.
+func TestSegmentEvent_Fixtures(t *testing.T) {
+	now := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	paths, err := filepath.Glob("testdata/segment/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".json"), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var fixture struct {
+				Message models.SegmentMessage `json:"message"`
+				Event   json.RawMessage       `json:"event"`
+				Error   string                `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(data, &fixture))
+
+			event, err := segmentEvent(&fixture.Message, segmentTestConfig.TrackEvents, now)
+			if fixture.Error != "" {
+				assert.EqualError(t, err, fixture.Error)
+				return
+			}
+			require.NoError(t, err)
+			actual, err := json.Marshal(event)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(fixture.Event), string(actual))
+			if event != nil {
+				assert.NoError(t, event.Validate())
+			}
+		})
+	}
+}
+
+func TestSegmentService_Ingest(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewSegmentService(NewEventsService(store), NewUsersService(store, config.AnalyticsConfig{}), segmentTestConfig)
+	timestamp := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+
+	messages := []models.SegmentMessage{
+		{Type: "page", MessageID: "1", AnonymousID: "anon", Properties: map[string]any{"path": "/home"}, Timestamp: &timestamp},
+		{Type: "page", MessageID: "1", AnonymousID: "anon", Properties: map[string]any{"path": "/home"}, Timestamp: &timestamp},
+		{Type: "identify", MessageID: "2", AnonymousID: "anon", UserID: "alice"},
+		{Type: "track", MessageID: "3", UserID: "alice", Event: "Order Completed", Properties: map[string]any{"revenue": 5.0}},
+		{Type: "screen", MessageID: "4", UserID: "alice"},
+	}
+	result, err := service.Ingest(context.Background(), messages, "GB")
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.Equal(t, []models.SegmentRejection{
+		{MessageID: "3", Error: "product_id is required for purchase events"},
+		{MessageID: "4", Error: `unsupported message type "screen"`},
+	}, result.Rejected)
+
+	event, err := store.FindById(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "GB", event.Properties.Country)
+	assert.Equal(t, timestamp, *event.Timestamp)
+	// identify linked the visitor's anonymousId to alice.
+	assert.Equal(t, "alice", event.UserID)
+
+	result, err = service.Ingest(context.Background(), []models.SegmentMessage{
+		{Type: "alias", MessageID: "5", PreviousID: "old-alice", UserID: "alice"},
+		{Type: "alias", MessageID: "6", PreviousID: "anon", UserID: "bob"},
+	}, "")
+	require.NoError(t, err)
+	assert.Equal(t, []models.SegmentRejection{
+		{MessageID: "6", Error: ErrAliasConflict.Error()},
+	}, result.Rejected)
+	identity, err := store.Identity(context.Background(), "old-alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"anon", "old-alice"}, identity.Aliases)
+}
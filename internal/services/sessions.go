@@ -0,0 +1,85 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+type session struct {
+	ID        string
+	UserID    string
+	Start     time.Time
+	End       time.Time
+	Events    int
+	PageViews int
+}
+
+func (s *session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// sessionize splits one user's time-ordered events into sessions wherever
+// consecutive events are more than gap apart.
+func sessionize(events []*models.Event, gap time.Duration) []*session {
+	sessions := make([]*session, 0)
+	var current *session
+	for _, event := range events {
+		if current == nil || event.Timestamp.Sub(current.End) > gap {
+			current = &session{
+				ID:     sessionID(event.UserID, *event.Timestamp),
+				UserID: event.UserID,
+				Start:  *event.Timestamp,
+			}
+			sessions = append(sessions, current)
+		}
+		current.End = *event.Timestamp
+		current.Events++
+		if event.EventType == models.EventTypePageView {
+			current.PageViews++
+		}
+	}
+	return sessions
+}
+
+// sessionID identifies a session by its user and start, so a session has
+// the same ID on every query for as long as its first event stays first.
+func sessionID(userID string, start time.Time) string {
+	hash := sha256.Sum256([]byte(userID + "\x00" + start.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(hash[:16])
+}
+
+func sessionMetrics(events []*models.Event, gap time.Duration) *models.SessionMetrics {
+	metrics := &models.SessionMetrics{SessionGap: gap.String()}
+
+	var durations []float64
+	var totalEvents, pageViews, bounces int
+	for _, userEvents := range groupByUser(events) {
+		for _, session := range sessionize(userEvents, gap) {
+			durations = append(durations, session.Duration().Seconds())
+			totalEvents += session.Events
+			pageViews += session.PageViews
+			if session.Events == 1 {
+				bounces++
+			}
+		}
+	}
+
+	metrics.Sessions = len(durations)
+	if metrics.Sessions == 0 {
+		return metrics
+	}
+	total := 0.0
+	for _, duration := range durations {
+		total += duration
+	}
+	sessions := float64(metrics.Sessions)
+	metrics.MeanDurationSeconds = total / sessions
+	metrics.MedianDurationSeconds = median(durations)
+	metrics.BounceRate = float64(bounces) / sessions
+	metrics.EventsPerSession = float64(totalEvents) / sessions
+	metrics.PagesPerSession = float64(pageViews) / sessions
+	return metrics
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionize(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []*models.Event{
+		newTestEvent("123", models.EventTypePageView, t0),
+		newTestEvent("123", models.EventTypeClick, t0.Add(10*time.Minute)),
+		newTestEvent("123", models.EventTypePageView, t0.Add(40*time.Minute)),
+		// More than 30 minutes after the last event starts a new session.
+		newTestEvent("123", models.EventTypePageView, t0.Add(71*time.Minute)),
+	}
+
+	sessions := sessionize(events, 30*time.Minute)
+	require.Len(t, sessions, 2)
+
+	assert.Equal(t, t0, sessions[0].Start)
+	assert.Equal(t, sessionID("123", t0), sessions[0].ID)
+	assert.Equal(t, 40*time.Minute, sessions[0].Duration())
+	assert.Equal(t, 3, sessions[0].Events)
+	assert.Equal(t, 2, sessions[0].PageViews)
+
+	assert.Equal(t, t0.Add(71*time.Minute), sessions[1].Start)
+	assert.NotEqual(t, sessions[0].ID, sessions[1].ID)
+	// The ID is derived from the session, so sessionizing again gives the same.
+	assert.Equal(t, sessions[1].ID, sessionize(events[3:], 30*time.Minute)[0].ID)
+	assert.Equal(t, time.Duration(0), sessions[1].Duration())
+	assert.Equal(t, 1, sessions[1].Events)
+}
+
+func TestSessionMetrics(t *testing.T) {
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	events := []*models.Event{
+		newTestEvent("a", models.EventTypePageView, t0),
+		newTestEvent("a", models.EventTypePageView, t0.Add(20*time.Minute)),
+		newTestEvent("a", models.EventTypeClick, t0.Add(3*time.Hour)),
+		newTestEvent("b", models.EventTypePageView, t0.Add(time.Hour)),
+		newTestEvent("b", models.EventTypePurchase, t0.Add(time.Hour+10*time.Minute)),
+	}
+
+	metrics := sessionMetrics(events, 30*time.Minute)
+
+	assert.Equal(t, "30m0s", metrics.SessionGap)
+	assert.Equal(t, 3, metrics.Sessions)
+	assert.Equal(t, 600.0, metrics.MeanDurationSeconds)
+	assert.Equal(t, 600.0, metrics.MedianDurationSeconds)
+	assert.InDelta(t, 1.0/3, metrics.BounceRate, 1e-9)
+	assert.InDelta(t, 5.0/3, metrics.EventsPerSession, 1e-9)
+	assert.Equal(t, 1.0, metrics.PagesPerSession)
+}
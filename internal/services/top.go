@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+func (s *analyticsService) GetTop(ctx context.Context, query *models.TopQuery) (*models.TopItems, error) {
+	exact := query.Mode == models.TopModeExact
+	if query.Mode == models.TopModeAuto {
+		exact = s.rollups.Count(query.Start, query.End.Add(time.Nanosecond)) <= s.config.ExactTopLimit
+	}
+
+	result := &models.TopItems{
+		Dimension: query.Dimension,
+		Start:     query.Start,
+		End:       query.End,
+		Items:     make([]models.TopItem, 0),
+	}
+	if !exact {
+		sketch := s.rollups.Top(query.Dimension, &query.Start, &query.End)
+		result.Approximate = !sketch.Exact()
+		for _, item := range sketch.Top(query.Limit) {
+			result.Items = append(result.Items, models.TopItem{
+				Key:   item.Key,
+				Count: item.Count,
+				Error: item.Error,
+			})
+		}
+		return result, nil
+	}
+
+	events, err := s.storage.FindAll(ctx, &models.EventFilter{
+		StartTimestamp: &query.Start,
+		EndTimestamp:   &query.End,
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, event := range events {
+		if key, ok := query.Dimension.Key(event); ok {
+			counts[key]++
+		}
+	}
+	for key, count := range counts {
+		result.Items = append(result.Items, models.TopItem{Key: key, Count: count})
+	}
+	sort.Slice(result.Items, func(i, j int) bool {
+		if result.Items[i].Count != result.Items[j].Count {
+			return result.Items[i].Count > result.Items[j].Count
+		}
+		return result.Items[i].Key < result.Items[j].Key
+	})
+	if len(result.Items) > query.Limit {
+		result.Items = result.Items[:query.Limit]
+	}
+	return result, nil
+}
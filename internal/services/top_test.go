@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyticsService_GetTop(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewAnalyticsService(store, config.AnalyticsConfig{ExactTopLimit: 1000})
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	save := func(userID string, eventType models.EventType, offset time.Duration, page string) {
+		event := newTestEvent(userID, eventType, t0.Add(offset))
+		event.Properties.Page = page
+		require.NoError(t, store.Save(ctx, event))
+	}
+	for i := 0; i < 6; i++ {
+		save("a", models.EventTypePageView, time.Duration(i)*time.Minute, "/home")
+	}
+	for i := 0; i < 3; i++ {
+		save("b", models.EventTypePageView, time.Duration(i)*time.Hour+30*time.Second, "/pricing")
+	}
+	save("b", models.EventTypeClick, 0, "/home")
+	// Outside the range.
+	save("c", models.EventTypePageView, -time.Minute, "/about")
+
+	for _, mode := range []models.TopMode{models.TopModeAuto, models.TopModeExact, models.TopModeApproximate} {
+		t.Run(string(mode), func(t *testing.T) {
+			top, err := service.GetTop(ctx, &models.TopQuery{
+				Dimension: models.TopDimensionPages,
+				Start:     t0,
+				End:       t0.Add(3 * time.Hour),
+				Limit:     10,
+				Mode:      mode,
+			})
+			require.NoError(t, err)
+			assert.False(t, top.Approximate)
+			assert.Equal(t, []models.TopItem{
+				{Key: "/home", Count: 6},
+				{Key: "/pricing", Count: 3},
+			}, top.Items)
+		})
+	}
+
+	t.Run("approximate over capacity", func(t *testing.T) {
+		// More users than a sketch tracks, which is ten times MaxTopLimit.
+		for i := 0; i < 20*models.MaxTopLimit; i++ {
+			save(fmt.Sprintf("user-%d", i), models.EventTypeClick, 2*time.Hour, "")
+		}
+		top, err := service.GetTop(ctx, &models.TopQuery{
+			Dimension: models.TopDimensionUsers,
+			Start:     t0,
+			End:       t0.Add(3 * time.Hour),
+			Limit:     2,
+			Mode:      models.TopModeApproximate,
+		})
+		require.NoError(t, err)
+		assert.True(t, top.Approximate)
+		require.Len(t, top.Items, 2)
+		for _, item := range top.Items {
+			assert.LessOrEqual(t, item.Count-item.Error, 7)
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/aggregation"
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+)
+
+var (
+	ErrUserNotFound  = errors.New("user not found")
+	ErrAliasConflict = errors.New("previous_id is already an alias of another user")
+)
+
+type UsersService interface {
+	GetUserProfile(ctx context.Context, userID string) (*models.UserProfile, error)
+	GetUserTimeline(ctx context.Context, userID string, offset, limit int) (*models.UserTimeline, error)
+	// AliasUser links previousID, such as the anonymous ID of a visitor who
+	// has since signed up, to userID, merging the histories stored under
+	// both. Linking IDs already linked is a no-op.
+	AliasUser(ctx context.Context, userID, previousID string) (*models.Identity, error)
+}
+
+type usersService struct {
+	storage    storage.EventStorage
+	converter  *aggregation.CurrencyConverter
+	sessionGap time.Duration
+}
+
+func NewUsersService(storage storage.EventStorage, config config.AnalyticsConfig) UsersService {
+	return &usersService{
+		storage:    storage,
+		converter:  aggregation.NewCurrencyConverter(config.BaseCurrency, config.ExchangeRates),
+		sessionGap: config.SessionGap,
+	}
+}
+
+func (s *usersService) GetUserProfile(ctx context.Context, userID string) (*models.UserProfile, error) {
+	identity, err := s.storage.Identity(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.storage.FindByUser(ctx, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	profile := &models.UserProfile{
+		UserID:       identity.UserID,
+		Aliases:      identity.Aliases,
+		TotalEvents:  len(events),
+		EventsByType: eventsByType(events),
+		Currency:     s.converter.Base(),
+	}
+	var total float64
+	for _, event := range events {
+		if event.Timestamp == nil {
+			continue
+		}
+		if profile.FirstSeen.IsZero() {
+			profile.FirstSeen = *event.Timestamp
+		}
+		profile.LastSeen = *event.Timestamp
+
+		switch event.EventType {
+		case models.EventTypePageView:
+			profile.LastPageViewed = event.Properties.Page
+		case models.EventTypePurchase:
+			if amount, ok := s.converter.Convert(event.Properties.Amount, event.Properties.Currency); ok {
+				total += amount
+			}
+		}
+	}
+	profile.TotalPurchaseAmount = roundCents(total)
+	return profile, nil
+}
+
+func (s *usersService) GetUserTimeline(ctx context.Context, userID string, offset, limit int) (*models.UserTimeline, error) {
+	identity, err := s.storage.Identity(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.storage.FindByUser(ctx, identity.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	timeline := &models.UserTimeline{
+		UserID: identity.UserID,
+		Total:  len(events),
+		Offset: offset,
+		Limit:  limit,
+		Events: make([]*models.TimelineEvent, 0),
+	}
+	if offset < len(events) {
+		end := min(offset+limit, len(events))
+		sessionIDs := timelineSessions(events[:end], s.sessionGap)
+		for i := offset; i < end; i++ {
+			timeline.Events = append(timeline.Events, &models.TimelineEvent{Event: events[i], SessionID: sessionIDs[i]})
+		}
+		if end < len(events) {
+			timeline.NextOffset = &end
+		}
+	}
+	return timeline, nil
+}
+
+// timelineSessions returns the session ID of each of a user's events, in
+// storage order: events without a timestamp, which have no session, first.
+func timelineSessions(events []*models.Event, gap time.Duration) []string {
+	ids := make([]string, len(events))
+	i := 0
+	for i < len(events) && events[i].Timestamp == nil {
+		i++
+	}
+	for _, session := range sessionize(events[i:], gap) {
+		for range session.Events {
+			ids[i] = session.ID
+			i++
+		}
+	}
+	return ids
+}
+
+func (s *usersService) AliasUser(ctx context.Context, userID, previousID string) (*models.Identity, error) {
+	if userID == "" || previousID == "" {
+		return nil, &ValidationError{Err: errors.New("user_id and previous_id are required")}
+	}
+	if userID == previousID {
+		return nil, &ValidationError{Err: errors.New("previous_id must differ from user_id")}
+	}
+	identity, err := s.storage.Alias(ctx, previousID, userID)
+	if errors.Is(err, storage.ErrAliasConflict) {
+		return nil, ErrAliasConflict
+	}
+	return identity, err
+}
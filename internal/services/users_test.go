@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsersService_AliasUser(t *testing.T) {
+	store := storage.NewEventStorage()
+	service := NewUsersService(store, config.AnalyticsConfig{})
+	analytics := NewAnalyticsService(store, config.AnalyticsConfig{})
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	pageView := newTestEvent("anon-1", models.EventTypePageView, t0)
+	pageView.Properties.Page = "/pricing"
+	require.NoError(t, store.Save(ctx, pageView))
+	require.NoError(t, store.Save(ctx, newTestEvent("alice", models.EventTypeSignup, t0.Add(time.Hour))))
+
+	identity, err := service.AliasUser(ctx, "alice", "anon-1")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Identity{UserID: "alice", Aliases: []string{"anon-1"}}, identity)
+
+	// Events arriving under the alias afterwards belong to alice too.
+	require.NoError(t, store.Save(ctx, newTestEvent("anon-1", models.EventTypePurchase, t0.Add(2*time.Hour))))
+
+	profile, err := service.GetUserProfile(ctx, "anon-1")
+	require.NoError(t, err)
+	assert.Equal(t, "alice", profile.UserID)
+	assert.Equal(t, []string{"anon-1"}, profile.Aliases)
+	assert.Equal(t, 3, profile.TotalEvents)
+	assert.Equal(t, t0, profile.FirstSeen)
+
+	timeline, err := service.GetUserTimeline(ctx, "alice", 0, models.DefaultTimelineLimit)
+	require.NoError(t, err)
+	assert.Equal(t, 3, timeline.Total)
+	// With no session gap configured, each event is a session of its own.
+	assert.Equal(t, sessionID("alice", t0), timeline.Events[0].SessionID)
+	assert.NotEqual(t, timeline.Events[0].SessionID, timeline.Events[1].SessionID)
+
+	// The visit before signing up and the signup count as one user's funnel.
+	start, end := t0.Add(-time.Hour), t0.Add(24*time.Hour)
+	req := &models.FunnelRequest{
+		Steps: []models.FunnelStep{
+			{Name: "viewed pricing", EventType: models.EventTypePageView},
+			{Name: "signed up", EventType: models.EventTypeSignup},
+		},
+		ConversionWindow: "24h",
+		Start:            &start,
+		End:              &end,
+	}
+	require.NoError(t, req.Validate())
+	funnel, err := analytics.GetFunnel(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, funnel.Steps[0].Users)
+	assert.Equal(t, 1, funnel.Steps[1].Users)
+
+	// An anonymous ID can't belong to two users.
+	_, err = service.AliasUser(ctx, "bob", "anon-1")
+	assert.ErrorIs(t, err, ErrAliasConflict)
+
+	var invalid *ValidationError
+	_, err = service.AliasUser(ctx, "alice", "alice")
+	assert.ErrorAs(t, err, &invalid)
+	_, err = service.AliasUser(ctx, "alice", "")
+	assert.ErrorAs(t, err, &invalid)
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+
+	// maxDeadLetters caps the dead letters kept per subscription, oldest
+	// dropped first.
+	maxDeadLetters = 1000
+
+	defaultWebhookBufferSize = 10000
+)
+
+var (
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("dead letter not found")
+	ErrWebhooksClosed       = errors.New("webhooks are shutting down")
+)
+
+type WebhooksService interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) []models.WebhookSubscription
+	GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeadLetters(ctx context.Context, id string) ([]models.WebhookDelivery, error)
+	// Redeliver queues a dead letter to be delivered again.
+	Redeliver(ctx context.Context, id, deliveryID string) error
+	// Close stops queueing events and waits for those already queued to be
+	// delivered. Deliveries still under way when ctx is done are abandoned.
+	Close(ctx context.Context) error
+}
+
+type webhooksService struct {
+	config config.WebhooksConfig
+	client *http.Client
+	// ctx is cancelled to abandon deliveries, once Close gives up on them.
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	wg     sync.WaitGroup
+
+	mutex         sync.RWMutex
+	subscriptions map[string]*subscription
+	closed        bool
+}
+
+// subscription buffers the events for one webhook between flushes. Each has
+// its own goroutine, so a slow or failing endpoint only holds up itself.
+type subscription struct {
+	models.WebhookSubscription
+	flushInterval time.Duration
+	cancel        context.CancelFunc
+
+	mutex       sync.Mutex
+	pending     []*models.Event
+	dropped     int
+	redeliver   []*models.WebhookDelivery
+	deadLetters []*models.WebhookDelivery
+	wake        chan struct{}
+}
+
+func NewWebhooksService(storage storage.EventStorage, config config.WebhooksConfig) WebhooksService {
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultWebhookBufferSize
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &webhooksService{
+		config:        config,
+		client:        &http.Client{Timeout: config.Timeout},
+		ctx:           ctx,
+		cancel:        cancel,
+		stop:          make(chan struct{}),
+		subscriptions: make(map[string]*subscription),
+	}
+	storage.AddObserver(s)
+	return s
+}
+
+func (s *webhooksService) CreateSubscription(_ context.Context, req *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	created := *req
+	created.ID = uuid.New().String()
+	created.Status = nil
+	created.CreatedAt = time.Now().UTC()
+	if created.BatchSize == 0 {
+		created.BatchSize = models.DefaultWebhookBatchSize
+	}
+	if created.FlushInterval == "" {
+		created.FlushInterval = models.DefaultWebhookFlushInterval
+	}
+	if created.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		created.Secret = hex.EncodeToString(secret)
+	}
+	flushInterval, err := time.ParseDuration(created.FlushInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return nil, ErrWebhooksClosed
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	sub := &subscription{
+		WebhookSubscription: created,
+		flushInterval:       flushInterval,
+		cancel:              cancel,
+		wake:                make(chan struct{}, 1),
+	}
+	s.subscriptions[created.ID] = sub
+	s.wg.Add(1)
+	go s.dispatch(ctx, sub)
+
+	return &created, nil
+}
+
+func (s *webhooksService) ListSubscriptions(_ context.Context) []models.WebhookSubscription {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	subscriptions := make([]models.WebhookSubscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		subscriptions = append(subscriptions, sub.redacted())
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions
+}
+
+func (s *webhooksService) GetSubscription(_ context.Context, id string) (*models.WebhookSubscription, error) {
+	sub, err := s.subscription(id)
+	if err != nil {
+		return nil, err
+	}
+	redacted := sub.redacted()
+	return &redacted, nil
+}
+
+// DeleteSubscription stops delivery at once; events still buffered or being
+// retried are dropped.
+func (s *webhooksService) DeleteSubscription(_ context.Context, id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+	sub.cancel()
+	delete(s.subscriptions, id)
+	return nil
+}
+
+func (s *webhooksService) ListDeadLetters(_ context.Context, id string) ([]models.WebhookDelivery, error) {
+	sub, err := s.subscription(id)
+	if err != nil {
+		return nil, err
+	}
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+	deadLetters := make([]models.WebhookDelivery, len(sub.deadLetters))
+	for i, delivery := range sub.deadLetters {
+		deadLetters[i] = *delivery
+	}
+	return deadLetters, nil
+}
+
+func (s *webhooksService) Redeliver(_ context.Context, id, deliveryID string) error {
+	sub, err := s.subscription(id)
+	if err != nil {
+		return err
+	}
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+	for i, delivery := range sub.deadLetters {
+		if delivery.ID == deliveryID {
+			sub.deadLetters = append(sub.deadLetters[:i], sub.deadLetters[i+1:]...)
+			delivery.Attempts = 0
+			delivery.LastError = ""
+			delivery.FailedAt = nil
+			sub.redeliver = append(sub.redeliver, delivery)
+			sub.signal()
+			return nil
+		}
+	}
+	return ErrDeliveryNotFound
+}
+
+func (s *webhooksService) subscription(id string) (*subscription, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	sub, ok := s.subscriptions[id]
+	if !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+	return sub, nil
+}
+
+// EventSaved queues new events. An event saved again, like one moved to
+// another user by an alias, has already been queued once.
+func (s *webhooksService) EventSaved(event, previous *models.Event) {
+	if previous != nil {
+		return
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if s.closed {
+		return
+	}
+	for _, sub := range s.subscriptions {
+		if !event.MatchesFilter(sub.Filter) {
+			continue
+		}
+		sub.mutex.Lock()
+		if len(sub.pending) < s.config.BufferSize {
+			sub.pending = append(sub.pending, event)
+		} else {
+			sub.dropped++
+		}
+		if len(sub.pending) >= sub.BatchSize {
+			sub.signal()
+		}
+		sub.mutex.Unlock()
+	}
+}
+
+func (s *webhooksService) EventDeleted(*models.Event) {}
+
+func (s *webhooksService) EventsCleared() {}
+
+func (s *webhooksService) Close(ctx context.Context) error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.stop)
+	s.mutex.Unlock()
+
+	stopDeliveries := context.AfterFunc(ctx, s.cancel)
+	defer stopDeliveries()
+	s.wg.Wait()
+	s.cancel()
+	return ctx.Err()
+}
+
+// dispatch delivers a subscription's events whenever a batch fills up or the
+// flush interval passes, until ctx is done. Once the service is closed it
+// delivers what is left and returns.
+func (s *webhooksService) dispatch(ctx context.Context, sub *subscription) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(sub.flushInterval)
+	defer ticker.Stop()
+	for {
+		stopping := false
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			stopping = true
+		case <-ticker.C:
+		case <-sub.wake:
+		}
+		for delivery := sub.next(); delivery != nil; delivery = sub.next() {
+			if !s.deliver(ctx, sub, delivery) {
+				return
+			}
+		}
+		if stopping {
+			return
+		}
+	}
+}
+
+// deliver attempts a delivery until it succeeds or runs out of attempts, in
+// which case it becomes a dead letter. It returns false if ctx was done.
+func (s *webhooksService) deliver(ctx context.Context, sub *subscription, delivery *models.WebhookDelivery) bool {
+	backoff := s.config.InitialBackoff
+	for {
+		delivery.Attempts++
+		err := s.post(ctx, sub, delivery)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= s.config.MaxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, s.config.MaxBackoff)
+	}
+
+	slog.Error("webhook delivery failed", "subscription", sub.ID, "delivery", delivery.ID, "error", delivery.LastError)
+	failedAt := time.Now().UTC()
+	delivery.FailedAt = &failedAt
+	sub.mutex.Lock()
+	sub.deadLetters = append(sub.deadLetters, delivery)
+	if len(sub.deadLetters) > maxDeadLetters {
+		sub.deadLetters = sub.deadLetters[len(sub.deadLetters)-maxDeadLetters:]
+	}
+	sub.mutex.Unlock()
+	return true
+}
+
+func (s *webhooksService) post(ctx context.Context, sub *subscription, delivery *models.WebhookDelivery) error {
+	body, err := json.Marshal(models.WebhookPayload{
+		DeliveryID:     delivery.ID,
+		SubscriptionID: sub.ID,
+		Events:         delivery.Events,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(sub.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of timestamp, a dot and body,
+// keyed with the subscription's secret. Receivers recompute it to check a
+// request is genuine and reject stale timestamps to stop replays.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// next returns the next delivery to attempt: redeliveries first, then a
+// batch of pending events.
+func (sub *subscription) next() *models.WebhookDelivery {
+	sub.mutex.Lock()
+	defer sub.mutex.Unlock()
+	if len(sub.redeliver) > 0 {
+		delivery := sub.redeliver[0]
+		sub.redeliver = sub.redeliver[1:]
+		return delivery
+	}
+	if len(sub.pending) == 0 {
+		return nil
+	}
+	n := min(len(sub.pending), sub.BatchSize)
+	delivery := &models.WebhookDelivery{
+		ID:             uuid.New().String(),
+		SubscriptionID: sub.ID,
+		Events:         sub.pending[:n:n],
+	}
+	sub.pending = sub.pending[n:]
+	return delivery
+}
+
+// signal wakes the dispatcher without blocking. Callers hold sub.mutex.
+func (sub *subscription) signal() {
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+}
+
+// redacted returns the subscription with its status and without its secret.
+func (sub *subscription) redacted() models.WebhookSubscription {
+	redacted := sub.WebhookSubscription
+	redacted.Secret = ""
+	sub.mutex.Lock()
+	redacted.Status = &models.WebhookStatus{
+		Buffered:    len(sub.pending),
+		Dropped:     sub.dropped,
+		DeadLetters: len(sub.deadLetters),
+	}
+	sub.mutex.Unlock()
+	return redacted
+}
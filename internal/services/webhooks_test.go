@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWebhooksConfig = config.WebhooksConfig{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Timeout:        time.Second,
+}
+
+// webhookReceiver records the payloads it accepts, after failing the first
+// failures requests.
+type webhookReceiver struct {
+	t        *testing.T
+	secret   string
+	failures atomic.Int32
+	mutex    sync.Mutex
+	payloads []models.WebhookPayload
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(r.t, err)
+	signature := SignWebhook(r.secret, req.Header.Get(WebhookTimestampHeader), body)
+	assert.Equal(r.t, "sha256="+signature, req.Header.Get(WebhookSignatureHeader))
+
+	if r.failures.Add(-1) >= 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var payload models.WebhookPayload
+	require.NoError(r.t, json.Unmarshal(body, &payload))
+	assert.Equal(r.t, payload.DeliveryID, req.Header.Get(WebhookDeliveryHeader))
+	r.mutex.Lock()
+	r.payloads = append(r.payloads, payload)
+	r.mutex.Unlock()
+}
+
+func (r *webhookReceiver) received() [][]string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	batches := make([][]string, len(r.payloads))
+	for i, payload := range r.payloads {
+		for _, event := range payload.Events {
+			batches[i] = append(batches[i], event.EventID)
+		}
+	}
+	return batches
+}
+
+func TestWebhooksService_Delivery(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "0123456789abcdef"}
+	receiver.failures.Store(2)
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	store := storage.NewEventStorage()
+	service := NewWebhooksService(store, testWebhooksConfig)
+	ctx := context.Background()
+	purchase := models.EventTypePurchase
+	subscription, err := service.CreateSubscription(ctx, &models.WebhookSubscription{
+		URL:           server.URL,
+		Filter:        &models.EventFilter{EventType: &purchase},
+		BatchSize:     2,
+		FlushInterval: "50ms",
+		Secret:        receiver.secret,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	for _, event := range []*models.Event{
+		{EventID: "a", UserID: "1", EventType: models.EventTypePurchase, Timestamp: &now},
+		{EventID: "b", UserID: "1", EventType: models.EventTypeClick, Timestamp: &now},
+		{EventID: "c", UserID: "2", EventType: models.EventTypePurchase, Timestamp: &now},
+		{EventID: "d", UserID: "3", EventType: models.EventTypePurchase, Timestamp: &now},
+	} {
+		require.NoError(t, store.Save(ctx, event))
+	}
+
+	// The first batch fills up and is retried past two failures; the rest is
+	// flushed on the interval.
+	assert.Eventually(t, func() bool {
+		return len(receiver.received()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]string{{"a", "c"}, {"d"}}, receiver.received())
+
+	deadLetters, err := service.ListDeadLetters(ctx, subscription.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deadLetters)
+}
+
+func TestWebhooksService_DeadLetters(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "0123456789abcdef"}
+	receiver.failures.Store(1000)
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	store := storage.NewEventStorage()
+	service := NewWebhooksService(store, testWebhooksConfig)
+	ctx := context.Background()
+	subscription, err := service.CreateSubscription(ctx, &models.WebhookSubscription{
+		URL:           server.URL,
+		BatchSize:     1,
+		FlushInterval: "50ms",
+		Secret:        receiver.secret,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, store.Save(ctx, &models.Event{EventID: "a", UserID: "1", EventType: models.EventTypeClick, Timestamp: &now}))
+
+	var deadLetters []models.WebhookDelivery
+	assert.Eventually(t, func() bool {
+		deadLetters, _ = service.ListDeadLetters(ctx, subscription.ID)
+		return len(deadLetters) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, testWebhooksConfig.MaxAttempts, deadLetters[0].Attempts)
+	assert.Contains(t, deadLetters[0].LastError, "503")
+	assert.Empty(t, receiver.received())
+
+	// The receiver recovers and the dead letter is redelivered.
+	receiver.failures.Store(0)
+	require.NoError(t, service.Redeliver(ctx, subscription.ID, deadLetters[0].ID))
+	assert.Eventually(t, func() bool {
+		return len(receiver.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, [][]string{{"a"}}, receiver.received())
+
+	deadLetters, err = service.ListDeadLetters(ctx, subscription.ID)
+	require.NoError(t, err)
+	assert.Empty(t, deadLetters)
+	assert.ErrorIs(t, service.Redeliver(ctx, subscription.ID, "missing"), ErrDeliveryNotFound)
+
+	require.NoError(t, service.DeleteSubscription(ctx, subscription.ID))
+	_, err = service.GetSubscription(ctx, subscription.ID)
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+}
+
+func TestWebhooksService_BufferFull(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "0123456789abcdef"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	store := storage.NewEventStorage()
+	cfg := testWebhooksConfig
+	cfg.BufferSize = 2
+	service := NewWebhooksService(store, cfg)
+	ctx := context.Background()
+	subscription, err := service.CreateSubscription(ctx, &models.WebhookSubscription{
+		URL:           server.URL,
+		BatchSize:     10,
+		FlushInterval: "1h",
+		Secret:        receiver.secret,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, subscription.Status)
+
+	now := time.Now()
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, store.Save(ctx, &models.Event{EventID: id, UserID: "1", EventType: models.EventTypeClick, Timestamp: &now}))
+	}
+
+	// Events beyond the buffer are dropped rather than held without limit.
+	subscription, err = service.GetSubscription(ctx, subscription.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &models.WebhookStatus{Buffered: 2, Dropped: 3}, subscription.Status)
+	assert.Equal(t, subscription.Status, service.ListSubscriptions(ctx)[0].Status)
+}
+
+func TestWebhooksService_AliasDoesNotRedeliver(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "0123456789abcdef"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	store := storage.NewEventStorage()
+	service := NewWebhooksService(store, testWebhooksConfig)
+	ctx := context.Background()
+	_, err := service.CreateSubscription(ctx, &models.WebhookSubscription{
+		URL:           server.URL,
+		BatchSize:     1,
+		FlushInterval: "10ms",
+		Secret:        receiver.secret,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	pageView := &models.Event{EventID: "p1", UserID: "anon", EventType: models.EventTypePageView, Timestamp: &now, Properties: models.EventProperties{Page: "/home"}}
+	require.NoError(t, store.Save(ctx, pageView))
+	assert.Eventually(t, func() bool {
+		return len(receiver.received()) == 1
+	}, time.Second, time.Millisecond)
+
+	// Moving the event to alice re-saves it, but it was delivered already.
+	_, err = store.Alias(ctx, "anon", "alice")
+	require.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, [][]string{{"p1"}}, receiver.received())
+}
+
+func TestWebhooksService_Close(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "0123456789abcdef"}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	store := storage.NewEventStorage()
+	service := NewWebhooksService(store, testWebhooksConfig)
+	ctx := context.Background()
+	_, err := service.CreateSubscription(ctx, &models.WebhookSubscription{
+		URL:           server.URL,
+		BatchSize:     10,
+		FlushInterval: "1h",
+		Secret:        receiver.secret,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, store.Save(ctx, &models.Event{EventID: "a", UserID: "1", EventType: models.EventTypeClick, Timestamp: &now}))
+
+	// Buffered events are delivered on close, not left for the next flush.
+	require.NoError(t, service.Close(ctx))
+	assert.Equal(t, [][]string{{"a"}}, receiver.received())
+
+	// Nothing more is taken once closed.
+	require.NoError(t, store.Save(ctx, &models.Event{EventID: "b", UserID: "1", EventType: models.EventTypeClick, Timestamp: &now}))
+	_, err = service.CreateSubscription(ctx, &models.WebhookSubscription{URL: server.URL})
+	assert.ErrorIs(t, err, ErrWebhooksClosed)
+	assert.Equal(t, &models.WebhookStatus{}, service.ListSubscriptions(ctx)[0].Status)
+	require.NoError(t, service.Close(ctx))
+}
+
+func TestWebhooksService_CloseGivesUp(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "0123456789abcdef"}
+	receiver.failures.Store(1000)
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	store := storage.NewEventStorage()
+	cfg := testWebhooksConfig
+	cfg.MaxAttempts = 1000
+	cfg.InitialBackoff = 10 * time.Millisecond
+	cfg.MaxBackoff = 10 * time.Millisecond
+	service := NewWebhooksService(store, cfg)
+	ctx := context.Background()
+	_, err := service.CreateSubscription(ctx, &models.WebhookSubscription{
+		URL:           server.URL,
+		BatchSize:     1,
+		FlushInterval: "1h",
+		Secret:        receiver.secret,
+	})
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, store.Save(ctx, &models.Event{EventID: "a", UserID: "1", EventType: models.EventTypeClick, Timestamp: &now}))
+
+	// A delivery still being retried is abandoned when ctx is done.
+	closeCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorIs(t, service.Close(closeCtx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Empty(t, receiver.received())
+}
@@ -0,0 +1,175 @@
+package sinks
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// fileTimeFormat names files by when they were opened, so they sort in
+// order.
+const fileTimeFormat = "20060102T150405.000000000Z"
+
+// fileSink appends events as NDJSON to a file in directory, starting a new
+// file once the current one reaches maxBytes or maxAge (zero disables
+// either). Finished files are gzipped when gzip is set.
+type fileSink struct {
+	directory string
+	maxBytes  int64
+	maxAge    time.Duration
+	gzip      bool
+	now       func() time.Time
+
+	file   sinkFile
+	size   int64
+	opened time.Time
+}
+
+// sinkFile is the part of *os.File a fileSink uses.
+type sinkFile interface {
+	io.WriteSeeker
+	io.Closer
+	Truncate(size int64) error
+	Name() string
+}
+
+func NewFileSink(directory string, maxBytes int64, maxAge time.Duration, gzip bool) (Sink, error) {
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileSink{
+		directory: directory,
+		maxBytes:  maxBytes,
+		maxAge:    maxAge,
+		gzip:      gzip,
+		now:       time.Now,
+	}, nil
+}
+
+func (s *fileSink) Write(_ context.Context, events []*models.Event) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	if s.file != nil && s.due(int64(buf.Len())) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		s.discard()
+		return err
+	}
+	s.size += int64(buf.Len())
+	return nil
+}
+
+// discard drops whatever part of a failed write reached the file, so the
+// retry doesn't leave a cut-off line or duplicate the events before it. If
+// the file can't be cut back, it is closed and the next write starts a new
+// one.
+func (s *fileSink) discard() {
+	err := s.file.Truncate(s.size)
+	if err == nil {
+		if _, err = s.file.Seek(s.size, io.SeekStart); err == nil {
+			return
+		}
+	}
+	path := s.file.Name()
+	slog.Error("failed to discard partial write, starting a new file", "file", path, "error", err.Error())
+	if err := s.rotate(); err != nil {
+		slog.Error("failed to finish file", "file", path, "error", err.Error())
+	}
+}
+
+// Rotate finishes the current file once it reaches maxAge, so a file is
+// rotated on time even when no more events arrive to write to it.
+func (s *fileSink) Rotate() error {
+	if s.file == nil || s.maxAge <= 0 || s.now().Sub(s.opened) < s.maxAge {
+		return nil
+	}
+	return s.rotate()
+}
+
+func (s *fileSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	return s.rotate()
+}
+
+// due reports whether the current file should be finished before writing
+// another n bytes.
+func (s *fileSink) due(n int64) bool {
+	if s.maxBytes > 0 && s.size > 0 && s.size+n > s.maxBytes {
+		return true
+	}
+	return s.maxAge > 0 && s.now().Sub(s.opened) >= s.maxAge
+}
+
+func (s *fileSink) open() error {
+	s.opened = s.now()
+	name := "events-" + s.opened.UTC().Format(fileTimeFormat) + ".ndjson"
+	file, err := os.OpenFile(filepath.Join(s.directory, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	s.size = 0
+	return nil
+}
+
+// rotate finishes the current file, gzipping it if configured.
+func (s *fileSink) rotate() error {
+	path := s.file.Name()
+	err := s.file.Close()
+	s.file = nil
+	if err != nil || !s.gzip {
+		return err
+	}
+	return gzipFile(path)
+}
+
+// gzipFile replaces path with path.gz.
+func gzipFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(out)
+	if _, err := io.Copy(writer, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
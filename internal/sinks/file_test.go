@@ -0,0 +1,153 @@
+package sinks
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink_Rotation(t *testing.T) {
+	directory := t.TempDir()
+	sink, err := NewFileSink(directory, 150, time.Hour, true)
+	require.NoError(t, err)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	sink.(*fileSink).now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Each event is a little under 150 bytes of NDJSON, so each write after
+	// the first rotates by size.
+	require.NoError(t, sink.Write(ctx, testEvents("a")))
+	now = now.Add(time.Second)
+	require.NoError(t, sink.Write(ctx, testEvents("b")))
+	// Rotated by age.
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, sink.Write(ctx, testEvents("c")))
+	require.NoError(t, sink.Close())
+
+	files, err := filepath.Glob(filepath.Join(directory, "*"))
+	require.NoError(t, err)
+	sort.Strings(files)
+	require.Len(t, files, 3)
+
+	var ids []string
+	for _, file := range files {
+		assert.Equal(t, ".gz", filepath.Ext(file))
+		ids = append(ids, readGzipNDJSON(t, file)...)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+}
+
+func TestFileSink_Uncompressed(t *testing.T) {
+	directory := t.TempDir()
+	sink, err := NewFileSink(directory, 0, 0, false)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Write(context.Background(), testEvents("a", "b")))
+	require.NoError(t, sink.Write(context.Background(), testEvents("c")))
+	require.NoError(t, sink.Close())
+
+	files, err := filepath.Glob(filepath.Join(directory, "*.ndjson"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(data), "\n"))
+}
+
+func readGzipNDJSON(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	var ids []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var event models.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		ids = append(ids, event.EventID)
+	}
+	require.NoError(t, scanner.Err())
+	return ids
+}
+
+func TestFileSink_RotateIdle(t *testing.T) {
+	directory := t.TempDir()
+	sink, err := NewFileSink(directory, 0, time.Hour, true)
+	require.NoError(t, err)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	sink.(*fileSink).now = func() time.Time { return now }
+	file := sink.(rotator)
+
+	require.NoError(t, sink.Write(context.Background(), testEvents("a")))
+	now = now.Add(30 * time.Minute)
+	require.NoError(t, file.Rotate())
+	gzipped, err := filepath.Glob(filepath.Join(directory, "*.gz"))
+	require.NoError(t, err)
+	assert.Empty(t, gzipped, "not due yet")
+
+	// No more events arrive, but the file is finished on time.
+	now = now.Add(time.Hour)
+	require.NoError(t, file.Rotate())
+	gzipped, err = filepath.Glob(filepath.Join(directory, "*.gz"))
+	require.NoError(t, err)
+	require.Len(t, gzipped, 1)
+	assert.Equal(t, []string{"a"}, readGzipNDJSON(t, gzipped[0]))
+	require.NoError(t, file.Rotate(), "nothing open")
+	require.NoError(t, sink.Close())
+}
+
+// shortFile writes only the first limit bytes it is given, then fails.
+type shortFile struct {
+	sinkFile
+	limit int
+}
+
+func (f *shortFile) Write(p []byte) (int, error) {
+	n, _ := f.sinkFile.Write(p[:min(len(p), f.limit)])
+	return n, errors.New("disk full")
+}
+
+func TestFileSink_PartialWrite(t *testing.T) {
+	directory := t.TempDir()
+	sink, err := NewFileSink(directory, 0, 0, false)
+	require.NoError(t, err)
+	file := sink.(*fileSink)
+	ctx := context.Background()
+
+	require.NoError(t, sink.Write(ctx, testEvents("a")))
+	healthy := file.file
+	file.file = &shortFile{sinkFile: healthy, limit: 20}
+	assert.EqualError(t, sink.Write(ctx, testEvents("b", "c")), "disk full")
+
+	// The retry writes the batch once, after the events already written.
+	file.file = healthy
+	require.NoError(t, sink.Write(ctx, testEvents("b", "c")))
+	require.NoError(t, sink.Close())
+
+	files, err := filepath.Glob(filepath.Join(directory, "*.ndjson"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		var event models.Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		ids = append(ids, event.EventID)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+}
@@ -0,0 +1,99 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const defaultKafkaTimeout = 10 * time.Second
+
+// kafkaSink produces each event to topic, keyed by user ID. Records with the
+// same key always land on the same partition, so each user's events keep
+// their order.
+type kafkaSink struct {
+	client *kgo.Client
+	topic  string
+}
+
+// NewKafkaSink connects lazily, so an unreachable cluster only shows up as
+// failed writes. compression is none, gzip, snappy, lz4 or zstd, and timeout
+// bounds how long a record may wait to be acknowledged.
+func NewKafkaSink(brokers []string, topic, compression string, timeout time.Duration) (Sink, error) {
+	codec, err := kafkaCompression(compression)
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = defaultKafkaTimeout
+	}
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.DefaultProduceTopic(topic),
+		kgo.ProducerBatchCompression(codec),
+		kgo.RecordDeliveryTimeout(timeout),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaSink{client: client, topic: topic}, nil
+}
+
+// Write waits for the broker to acknowledge every record. If any fail the
+// whole batch is retried, so consumers may see some events twice.
+func (s *kafkaSink) Write(ctx context.Context, events []*models.Event) error {
+	records := make([]*kgo.Record, len(events))
+	for i, event := range events {
+		value, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		records[i] = &kgo.Record{
+			Topic: s.topic,
+			Key:   []byte(event.UserID),
+			Value: value,
+		}
+	}
+
+	var failed int
+	var firstErr error
+	for _, result := range s.client.ProduceSync(ctx, records...) {
+		if result.Err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = result.Err
+			}
+		}
+	}
+	if firstErr != nil {
+		return fmt.Errorf("%d of %d events not acknowledged: %w", failed, len(records), firstErr)
+	}
+	return nil
+}
+
+func (s *kafkaSink) Close() error {
+	s.client.Close()
+	return nil
+}
+
+func kafkaCompression(name string) (kgo.CompressionCodec, error) {
+	switch name {
+	case "", "none":
+		return kgo.NoCompression(), nil
+	case "gzip":
+		return kgo.GzipCompression(), nil
+	case "snappy":
+		return kgo.SnappyCompression(), nil
+	case "lz4":
+		return kgo.Lz4Compression(), nil
+	case "zstd":
+		return kgo.ZstdCompression(), nil
+	default:
+		return kgo.CompressionCodec{}, errors.New("compression must be none, gzip, snappy, lz4 or zstd")
+	}
+}
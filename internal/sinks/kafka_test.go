@@ -0,0 +1,103 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestKafkaSink_KeyedByUser(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(4, "events"))
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	sink, err := NewKafkaSink(cluster.ListenAddrs(), "events", "gzip", 5*time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	events := testEvents("1", "2", "3", "4", "5", "6")
+	for i, event := range events {
+		event.UserID = []string{"alice", "bob", "carol"}[i%3]
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, sink.Write(ctx, events[:3]))
+	require.NoError(t, sink.Write(ctx, events[3:]))
+
+	consumer, err := kgo.NewClient(
+		kgo.SeedBrokers(cluster.ListenAddrs()...),
+		kgo.ConsumeTopics("events"),
+	)
+	require.NoError(t, err)
+	defer consumer.Close()
+
+	var records []*kgo.Record
+	for len(records) < len(events) && ctx.Err() == nil {
+		fetches := consumer.PollFetches(ctx)
+		records = append(records, fetches.Records()...)
+	}
+	require.Len(t, records, len(events))
+
+	partitions := make(map[string]int32)
+	ids := make(map[string][]string)
+	for _, record := range records {
+		var event models.Event
+		require.NoError(t, json.Unmarshal(record.Value, &event))
+		user := string(record.Key)
+		assert.Equal(t, event.UserID, user)
+		if partition, ok := partitions[user]; ok {
+			assert.Equal(t, partition, record.Partition, "user %s split across partitions", user)
+		}
+		partitions[user] = record.Partition
+		ids[user] = append(ids[user], event.EventID)
+	}
+	assert.Equal(t, map[string][]string{
+		"alice": {"1", "4"},
+		"bob":   {"2", "5"},
+		"carol": {"3", "6"},
+	}, ids)
+}
+
+func TestKafkaSink_FailedDeliveryMarksUnhealthy(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1))
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	// The topic doesn't exist and the fake broker won't create it, so no
+	// record is ever acknowledged.
+	sink, err := NewKafkaSink(cluster.ListenAddrs(), "missing", "", time.Second)
+	require.NoError(t, err)
+	m, err := newManager([]config.SinkConfig{{
+		Name:          "platform",
+		Type:          "kafka",
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		MaxAttempts:   1,
+	}}, []Sink{sink})
+	require.NoError(t, err)
+	defer m.Close(context.Background())
+
+	m.Publish(testEvents("1", "2")...)
+
+	require.Eventually(t, func() bool {
+		return m.Statuses()[0].Failed == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	status := m.Statuses()[0]
+	assert.False(t, status.Healthy)
+	assert.Contains(t, status.LastError, "2 of 2 events not acknowledged")
+}
+
+func TestNew_KafkaValidation(t *testing.T) {
+	_, err := New(config.SinkConfig{Name: "platform", Type: "kafka", Topic: "events"})
+	assert.Error(t, err)
+	_, err = New(config.SinkConfig{Name: "platform", Type: "kafka", Brokers: []string{"localhost:9092"}, Topic: "events", Compression: "brotli"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,263 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+var ErrSinkNotFound = errors.New("sink not found")
+
+// Manager fans events out to its sinks. Each sink has its own buffer and
+// goroutine, so a slow or failing sink never holds up ingest or the others.
+type Manager struct {
+	runners []*runner
+	byName  map[string]*runner
+	stop    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type runner struct {
+	config config.SinkConfig
+	sink   Sink
+
+	mutex   sync.Mutex
+	pending []*models.Event
+	status  models.SinkStatus
+	wake    chan struct{}
+}
+
+// NewManager builds the configured sinks and starts delivering to them.
+func NewManager(configs []config.SinkConfig) (*Manager, error) {
+	sinks := make([]Sink, 0, len(configs))
+	for _, cfg := range configs {
+		sink, err := New(cfg)
+		if err != nil {
+			for _, sink := range sinks {
+				sink.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return newManager(configs, sinks)
+}
+
+// newManager starts delivering to sinks, each configured by the matching
+// entry of configs.
+func newManager(configs []config.SinkConfig, sinks []Sink) (*Manager, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{
+		byName: make(map[string]*runner),
+		stop:   make(chan struct{}),
+		cancel: cancel,
+	}
+	for i, sink := range sinks {
+		cfg := withDefaults(configs[i])
+		if _, ok := m.byName[cfg.Name]; ok || cfg.Name == "" {
+			cancel()
+			return nil, fmt.Errorf("sink names must be unique and not empty: %q", cfg.Name)
+		}
+		r := &runner{
+			config: cfg,
+			sink:   sink,
+			status: models.SinkStatus{Name: cfg.Name, Type: cfg.Type, Healthy: true},
+			wake:   make(chan struct{}, 1),
+		}
+		m.runners = append(m.runners, r)
+		m.byName[cfg.Name] = r
+	}
+	for _, r := range m.runners {
+		m.wg.Add(1)
+		go func(r *runner) {
+			defer m.wg.Done()
+			r.run(ctx, m.stop)
+		}(r)
+	}
+	return m, nil
+}
+
+// Publish buffers events for every sink without blocking. A sink whose
+// buffer is full drops them.
+func (m *Manager) Publish(events ...*models.Event) {
+	for _, r := range m.runners {
+		r.enqueue(events)
+	}
+}
+
+func (m *Manager) Statuses() []models.SinkStatus {
+	statuses := make([]models.SinkStatus, len(m.runners))
+	for i, r := range m.runners {
+		statuses[i] = r.snapshot()
+	}
+	return statuses
+}
+
+// SetPaused pauses or resumes a sink. A paused sink keeps buffering until
+// its buffer is full.
+func (m *Manager) SetPaused(name string, paused bool) (models.SinkStatus, error) {
+	r, ok := m.byName[name]
+	if !ok {
+		return models.SinkStatus{}, ErrSinkNotFound
+	}
+	r.mutex.Lock()
+	r.status.Paused = paused
+	r.signal()
+	r.mutex.Unlock()
+	return r.snapshot(), nil
+}
+
+// Close flushes what the sinks have buffered, giving up when ctx is done,
+// and closes them. A batch already being written or retried is finished
+// first rather than abandoned.
+func (m *Manager) Close(ctx context.Context) error {
+	close(m.stop)
+	stopWrites := context.AfterFunc(ctx, m.cancel)
+	defer stopWrites()
+	m.wg.Wait()
+	var errs []error
+	for _, r := range m.runners {
+		r.flush(ctx, 1, true)
+		if err := r.sink.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("sink %q: %w", r.config.Name, err))
+		}
+	}
+	m.cancel()
+	return errors.Join(errs...)
+}
+
+func (r *runner) enqueue(events []*models.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	room := r.config.BufferSize - len(r.pending)
+	if room < len(events) {
+		r.status.Dropped += len(events) - max(room, 0)
+		events = events[:max(room, 0)]
+	}
+	r.pending = append(r.pending, events...)
+	if len(r.pending) >= r.config.BatchSize {
+		r.signal()
+	}
+}
+
+// run writes full batches as soon as they fill up and everything else each
+// flush interval, until stop is closed. Writes are only cut short when ctx
+// is done.
+func (r *runner) run(ctx context.Context, stop <-chan struct{}) {
+	ticker := time.NewTicker(r.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			r.flush(ctx, 1, false)
+			r.rotate()
+		case <-r.wake:
+			r.flush(ctx, r.config.BatchSize, false)
+		}
+	}
+}
+
+// flush writes what is buffered in batches while at least minBatch events
+// are waiting. Unless force is set it stops while the sink is paused.
+func (r *runner) flush(ctx context.Context, minBatch int, force bool) {
+	for {
+		batch := r.next(minBatch, force)
+		if len(batch) == 0 {
+			return
+		}
+		err := r.write(ctx, batch)
+		now := time.Now().UTC()
+		r.mutex.Lock()
+		if err != nil {
+			r.status.Healthy = false
+			r.status.Failed += len(batch)
+			r.status.LastError = err.Error()
+			r.status.LastErrorAt = &now
+		} else {
+			r.status.Healthy = true
+			r.status.Delivered += len(batch)
+			r.status.LastFlushAt = &now
+		}
+		r.mutex.Unlock()
+		if err != nil {
+			slog.Error("sink write failed", "sink", r.config.Name, "events", len(batch), "error", err.Error())
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// rotate lets a sink that rotates on a schedule do so while idle.
+func (r *runner) rotate() {
+	sink, ok := r.sink.(rotator)
+	if !ok {
+		return
+	}
+	if err := sink.Rotate(); err != nil {
+		slog.Error("sink rotation failed", "sink", r.config.Name, "error", err.Error())
+		now := time.Now().UTC()
+		r.mutex.Lock()
+		r.status.LastError = err.Error()
+		r.status.LastErrorAt = &now
+		r.mutex.Unlock()
+	}
+}
+
+func (r *runner) next(minBatch int, force bool) []*models.Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if (r.status.Paused && !force) || len(r.pending) < minBatch {
+		return nil
+	}
+	n := min(len(r.pending), r.config.BatchSize)
+	batch := r.pending[:n:n]
+	r.pending = r.pending[n:]
+	return batch
+}
+
+// write retries a batch with exponential backoff until it succeeds, runs out
+// of attempts or ctx is done.
+func (r *runner) write(ctx context.Context, batch []*models.Event) error {
+	backoff := r.config.InitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = r.sink.Write(ctx, batch); err == nil {
+			return nil
+		}
+		if attempt >= r.config.MaxAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, r.config.MaxBackoff)
+	}
+}
+
+func (r *runner) snapshot() models.SinkStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	status := r.status
+	status.Buffered = len(r.pending)
+	return status
+}
+
+// signal wakes the runner without blocking. Callers hold r.mutex.
+func (r *runner) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
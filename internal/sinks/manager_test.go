@@ -0,0 +1,169 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSink records batches after failing the first failures writes.
+type fakeSink struct {
+	mutex    sync.Mutex
+	failures int
+	batches  [][]string
+	closed   bool
+}
+
+func (s *fakeSink) Write(_ context.Context, events []*models.Event) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.EventID
+	}
+	s.batches = append(s.batches, ids)
+	return nil
+}
+
+func (s *fakeSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSink) received() [][]string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([][]string(nil), s.batches...)
+}
+
+func testEvents(ids ...string) []*models.Event {
+	events := make([]*models.Event, len(ids))
+	for i, id := range ids {
+		events[i] = &models.Event{EventID: id}
+	}
+	return events
+}
+
+func TestManager_Delivery(t *testing.T) {
+	flaky := &fakeSink{failures: 2}
+	broken := &fakeSink{failures: 1000}
+	manager, err := newManager([]config.SinkConfig{
+		{Name: "flaky", BatchSize: 2, FlushInterval: time.Hour, MaxAttempts: 3, InitialBackoff: time.Millisecond},
+		{Name: "broken", BatchSize: 2, FlushInterval: time.Hour, MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	}, []Sink{flaky, broken})
+	require.NoError(t, err)
+
+	manager.Publish(testEvents("a", "b", "c")...)
+
+	// A full batch is written at once, retried past the failures.
+	assert.Eventually(t, func() bool {
+		return len(flaky.received()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, [][]string{{"a", "b"}}, flaky.received())
+	assert.Eventually(t, func() bool {
+		return manager.Statuses()[1].Failed == 2
+	}, time.Second, time.Millisecond)
+
+	statuses := manager.Statuses()
+	assert.True(t, statuses[0].Healthy)
+	assert.Equal(t, 2, statuses[0].Delivered)
+	assert.Equal(t, 1, statuses[0].Buffered)
+	assert.False(t, statuses[1].Healthy)
+	assert.Equal(t, "unavailable", statuses[1].LastError)
+
+	// Close flushes the rest.
+	require.NoError(t, manager.Close(context.Background()))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, flaky.received())
+	assert.True(t, flaky.closed)
+}
+
+func TestManager_Pause(t *testing.T) {
+	sink := &fakeSink{}
+	manager, err := newManager([]config.SinkConfig{
+		{Name: "archive", BatchSize: 1, BufferSize: 2, FlushInterval: time.Hour},
+	}, []Sink{sink})
+	require.NoError(t, err)
+
+	status, err := manager.SetPaused("archive", true)
+	require.NoError(t, err)
+	assert.True(t, status.Paused)
+
+	manager.Publish(testEvents("a", "b", "c")...)
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, sink.received())
+	status = manager.Statuses()[0]
+	assert.Equal(t, 2, status.Buffered)
+	assert.Equal(t, 1, status.Dropped)
+
+	_, err = manager.SetPaused("archive", false)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return len(sink.received()) == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, [][]string{{"a"}, {"b"}}, sink.received())
+
+	_, err = manager.SetPaused("missing", true)
+	assert.ErrorIs(t, err, ErrSinkNotFound)
+	require.NoError(t, manager.Close(context.Background()))
+}
+
+func TestManager_CloseDuringBackoff(t *testing.T) {
+	sink := &fakeSink{failures: 1}
+	manager, err := newManager([]config.SinkConfig{
+		{Name: "flaky", BatchSize: 2, FlushInterval: time.Hour, MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond},
+	}, []Sink{sink})
+	require.NoError(t, err)
+
+	manager.Publish(testEvents("a", "b", "c")...)
+	// The first batch failed once and is waiting to be retried.
+	require.Eventually(t, func() bool {
+		sink.mutex.Lock()
+		defer sink.mutex.Unlock()
+		return sink.failures == 0
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, manager.Close(context.Background()))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, sink.received())
+	status := manager.Statuses()[0]
+	assert.Equal(t, 3, status.Delivered)
+	assert.Equal(t, 0, status.Failed)
+}
+
+// rotatingSink counts the calls to Rotate.
+type rotatingSink struct {
+	fakeSink
+	rotations atomic.Int32
+}
+
+func (s *rotatingSink) Rotate() error {
+	s.rotations.Add(1)
+	return nil
+}
+
+func TestManager_RotatesWhileIdle(t *testing.T) {
+	sink := &rotatingSink{}
+	manager, err := newManager([]config.SinkConfig{
+		{Name: "archive", FlushInterval: time.Millisecond},
+	}, []Sink{sink})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return sink.rotations.Load() >= 2
+	}, time.Second, time.Millisecond)
+	require.NoError(t, manager.Close(context.Background()))
+	assert.Empty(t, sink.received())
+}
@@ -0,0 +1,85 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+const (
+	defaultBatchSize      = 100
+	defaultFlushInterval  = time.Second
+	defaultBufferSize     = 10000
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// Sink writes batches of events somewhere outside the service. Write is
+// retried on error, so it should leave nothing half written that a retry
+// can't repair. Sinks are only called from one goroutine at a time.
+type Sink interface {
+	Write(ctx context.Context, events []*models.Event) error
+	Close() error
+}
+
+// rotator is implemented by sinks that finish their output on a schedule,
+// not only when written to. Rotate is called each flush interval, whether or
+// not there was anything to write.
+type rotator interface {
+	Rotate() error
+}
+
+// New builds the sink described by cfg.
+func New(cfg config.SinkConfig) (Sink, error) {
+	switch cfg.Type {
+	case "file":
+		if cfg.Directory == "" {
+			return nil, fmt.Errorf("sink %q: directory is required", cfg.Name)
+		}
+		return NewFileSink(cfg.Directory, cfg.MaxBytes, cfg.MaxAge, cfg.Gzip)
+	case "stdout":
+		return NewStdoutSink(), nil
+	case "http":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("sink %q: url is required", cfg.Name)
+		}
+		return NewHTTPSink(cfg.URL, cfg.Timeout), nil
+	case "kafka":
+		if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+			return nil, fmt.Errorf("sink %q: brokers and topic are required", cfg.Name)
+		}
+		sink, err := NewKafkaSink(cfg.Brokers, cfg.Topic, cfg.Compression, cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("sink %q: %w", cfg.Name, err)
+		}
+		return sink, nil
+	default:
+		return nil, fmt.Errorf("sink %q: type must be file, stdout, http or kafka", cfg.Name)
+	}
+}
+
+func withDefaults(cfg config.SinkConfig) config.SinkConfig {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = defaultBufferSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	return cfg
+}
@@ -0,0 +1,33 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// stdoutSink writes events as NDJSON to standard output.
+type stdoutSink struct {
+	out io.Writer
+}
+
+func NewStdoutSink() Sink {
+	return &stdoutSink{out: os.Stdout}
+}
+
+func (s *stdoutSink) Write(_ context.Context, events []*models.Event) error {
+	encoder := json.NewEncoder(s.out)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *stdoutSink) Close() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const maxPollRecords = 500
+
+// kafkaSource consumes topic as a member of a consumer group. Offsets are
+// only committed by Commit, and partitions are not reassigned between a
+// Fetch and its Commit, so a rebalance never skips a message.
+type kafkaSource struct {
+	client *kgo.Client
+}
+
+func NewKafkaSource(brokers []string, topic, group string) (Source, error) {
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumerGroup(group),
+		kgo.DisableAutoCommit(),
+		kgo.BlockRebalanceOnPoll(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaSource{client: client}, nil
+}
+
+func (s *kafkaSource) Fetch(ctx context.Context) ([]Message, error) {
+	fetches := s.client.PollRecords(ctx, maxPollRecords)
+	var messages []Message
+	fetches.EachRecord(func(record *kgo.Record) {
+		messages = append(messages, Message{Value: record.Value, Ref: record})
+	})
+	if len(messages) == 0 {
+		s.client.AllowRebalance()
+		if errs := fetches.Errors(); len(errs) > 0 {
+			return nil, fmt.Errorf("fetching %s[%d]: %w", errs[0].Topic, errs[0].Partition, errs[0].Err)
+		}
+	}
+	return messages, nil
+}
+
+func (s *kafkaSource) Commit(ctx context.Context, messages []Message) error {
+	defer s.client.AllowRebalance()
+	if len(messages) == 0 {
+		return nil
+	}
+	records := make([]*kgo.Record, len(messages))
+	for i, message := range messages {
+		records[i] = message.Ref.(*kgo.Record)
+	}
+	return s.client.CommitRecords(ctx, records...)
+}
+
+func (s *kafkaSource) Close() error {
+	s.client.AllowRebalance()
+	s.client.Close()
+	return nil
+}
@@ -0,0 +1,60 @@
+package sources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestKafkaSource(t *testing.T) {
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(1, "incoming"))
+	require.NoError(t, err)
+	defer cluster.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	producer, err := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...), kgo.DefaultProduceTopic("incoming"))
+	require.NoError(t, err)
+	defer producer.Close()
+	for _, message := range []Message{
+		testMessage(t, "", "1", "/home"),
+		testMessage(t, "", "2", ""),
+		testMessage(t, "", "3", "/pricing"),
+		testMessage(t, "", "1", "/home"),
+	} {
+		require.NoError(t, producer.ProduceSync(ctx, &kgo.Record{Value: message.Value}).FirstErr())
+	}
+
+	source, err := NewKafkaSource(cluster.ListenAddrs(), "incoming", "ingest")
+	require.NoError(t, err)
+	store := storage.NewEventStorage()
+	m := newManager([]config.SourceConfig{{Name: "producers", Type: "kafka"}}, []Source{source}, services.NewEventsService(store))
+
+	admin := kadm.NewClient(producer)
+	require.Eventually(t, func() bool {
+		offsets, err := admin.FetchOffsets(ctx, "ingest")
+		if err != nil {
+			return false
+		}
+		offset, ok := offsets.Lookup("incoming", 0)
+		return ok && offset.At == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, m.Close(ctx))
+
+	events, err := store.FindAll(ctx, nil)
+	require.NoError(t, err)
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.EventID
+	}
+	assert.ElementsMatch(t, []string{"1", "3"}, ids)
+}
@@ -0,0 +1,161 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+)
+
+// Manager consumes from its sources, each in its own goroutine, and hands
+// the events to the same ingest path as the HTTP API.
+type Manager struct {
+	runners []*runner
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+type runner struct {
+	config   config.SourceConfig
+	source   Source
+	ingester Ingester
+}
+
+// NewManager builds the configured sources and starts consuming from them.
+func NewManager(configs []config.SourceConfig, ingester Ingester) (*Manager, error) {
+	sources := make([]Source, 0, len(configs))
+	for _, cfg := range configs {
+		source, err := New(cfg)
+		if err != nil {
+			for _, source := range sources {
+				source.Close()
+			}
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return newManager(configs, sources, ingester), nil
+}
+
+// newManager starts consuming from sources, each configured by the matching
+// entry of configs.
+func newManager(configs []config.SourceConfig, sources []Source, ingester Ingester) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := &Manager{cancel: cancel}
+	for i, source := range sources {
+		r := &runner{
+			config:   withDefaults(configs[i]),
+			source:   source,
+			ingester: ingester,
+		}
+		m.runners = append(m.runners, r)
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			r.run(ctx)
+		}()
+	}
+	return m
+}
+
+// Close stops consuming, waiting for messages being stored unless ctx is
+// done first, and closes the sources. Messages fetched but not committed are
+// delivered again on the next start.
+func (m *Manager) Close(ctx context.Context) error {
+	m.cancel()
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	var errs []error
+	for _, r := range m.runners {
+		if err := r.source.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("source %q: %w", r.config.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run fetches, stores and commits batches of messages until ctx is done.
+// A batch is only committed once every message in it has been stored or
+// rejected as invalid.
+func (r *runner) run(ctx context.Context) {
+	backoff := r.config.InitialBackoff
+	for {
+		messages, err := r.source.Fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			slog.Error("source fetch failed", "source", r.config.Name, "error", err.Error())
+			if !sleep(ctx, backoff) {
+				return
+			}
+			backoff = min(2*backoff, r.config.MaxBackoff)
+			continue
+		}
+		backoff = r.config.InitialBackoff
+
+		for _, message := range messages {
+			if !r.ingest(ctx, message) {
+				return
+			}
+		}
+		if err := r.source.Commit(ctx, messages); err != nil {
+			// Harmless beyond the extra work: the messages come round again
+			// and are deduplicated by event ID.
+			slog.Error("source commit failed", "source", r.config.Name, "error", err.Error())
+		}
+	}
+}
+
+// ingest stores the event in message, retrying until it is stored or ctx is
+// done, when it returns false. Messages that can never be stored are logged
+// and skipped so they don't hold up the rest.
+func (r *runner) ingest(ctx context.Context, message Message) bool {
+	var req models.CreateEventRequest
+	if err := json.Unmarshal(message.Value, &req); err != nil {
+		slog.Warn("skipping malformed message", "source", r.config.Name, "error", err.Error())
+		return true
+	}
+
+	backoff := r.config.InitialBackoff
+	for {
+		_, _, err := r.ingester.IngestEvent(ctx, &req, "")
+		var invalid *services.ValidationError
+		if errors.As(err, &invalid) {
+			slog.Warn("skipping invalid event", "source", r.config.Name, "event_id", req.EventID, "error", err.Error())
+			return true
+		}
+		if err == nil {
+			return true
+		}
+		slog.Error("failed to store event", "source", r.config.Name, "event_id", req.EventID, "error", err.Error())
+		if !sleep(ctx, backoff) {
+			return false
+		}
+		backoff = min(2*backoff, r.config.MaxBackoff)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
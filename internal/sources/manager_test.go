@@ -0,0 +1,117 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+	"github.com/dnakolan/event-processing-service/internal/services"
+	"github.com/dnakolan/event-processing-service/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource hands out its batches in turn and records what is committed,
+// along with how many events were stored by then.
+type fakeSource struct {
+	batches chan []Message
+	store   storage.EventStorage
+
+	mutex   sync.Mutex
+	commits [][]string
+	stored  []int
+}
+
+func (s *fakeSource) Fetch(ctx context.Context) ([]Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case batch := <-s.batches:
+		return batch, nil
+	}
+}
+
+func (s *fakeSource) Commit(ctx context.Context, messages []Message) error {
+	events, _ := s.store.FindAll(ctx, nil)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	refs := make([]string, len(messages))
+	for i, message := range messages {
+		refs[i] = message.Ref.(string)
+	}
+	s.commits = append(s.commits, refs)
+	s.stored = append(s.stored, len(events))
+	return nil
+}
+
+func (s *fakeSource) Close() error {
+	return nil
+}
+
+func (s *fakeSource) committed() ([][]string, []int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([][]string(nil), s.commits...), append([]int(nil), s.stored...)
+}
+
+// flakyIngester fails the first failures events it is given.
+type flakyIngester struct {
+	services.EventsService
+	failures int
+}
+
+func (i *flakyIngester) IngestEvent(ctx context.Context, req *models.CreateEventRequest, country string) (*models.Event, bool, error) {
+	if i.failures > 0 {
+		i.failures--
+		return nil, false, errors.New("storage unavailable")
+	}
+	return i.EventsService.IngestEvent(ctx, req, country)
+}
+
+func testMessage(t *testing.T, ref, eventID, page string) Message {
+	t.Helper()
+	now := time.Now()
+	value, err := json.Marshal(models.Event{
+		EventID:    eventID,
+		UserID:     "alice",
+		EventType:  models.EventTypePageView,
+		Timestamp:  &now,
+		Properties: models.EventProperties{Page: page},
+	})
+	require.NoError(t, err)
+	return Message{Value: value, Ref: ref}
+}
+
+func TestManager_CommitsAfterSave(t *testing.T) {
+	store := storage.NewEventStorage()
+	source := &fakeSource{batches: make(chan []Message, 2), store: store}
+	ingester := &flakyIngester{EventsService: services.NewEventsService(store), failures: 2}
+	m := newManager([]config.SourceConfig{{
+		Name:           "producers",
+		InitialBackoff: time.Millisecond,
+	}}, []Source{source}, ingester)
+	defer m.Close(context.Background())
+
+	source.batches <- []Message{
+		testMessage(t, "0", "1", "/home"),
+		{Value: []byte("not json"), Ref: "1"},
+		testMessage(t, "2", "2", ""),
+		testMessage(t, "3", "3", "/pricing"),
+	}
+	source.batches <- []Message{testMessage(t, "4", "1", "/home")}
+
+	require.Eventually(t, func() bool {
+		commits, _ := source.committed()
+		return len(commits) == 2
+	}, time.Second, time.Millisecond)
+	commits, stored := source.committed()
+	// Malformed and invalid messages are committed too, so they aren't
+	// redelivered forever, and the duplicate of event 1 isn't stored again.
+	assert.Equal(t, [][]string{{"0", "1", "2", "3"}, {"4"}}, commits)
+	assert.Equal(t, []int{2, 2}, stored)
+}
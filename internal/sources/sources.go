@@ -0,0 +1,72 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/config"
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultKafkaGroup     = "event-processing-service"
+)
+
+// Source pulls events from a message bus. Messages are delivered at least
+// once: anything fetched but not yet committed is fetched again after a
+// restart. Sources are only called from one goroutine at a time.
+type Source interface {
+	// Fetch blocks until there are messages or ctx is done.
+	Fetch(ctx context.Context) ([]Message, error)
+	// Commit acknowledges messages, which must have come from the last Fetch
+	// and all been handled.
+	Commit(ctx context.Context, messages []Message) error
+	Close() error
+}
+
+// Message is a JSON encoded event request. Ref is whatever the source needs
+// to commit it.
+type Message struct {
+	Value []byte
+	Ref   any
+}
+
+// Ingester stores events that arrived from outside the service, see
+// services.EventsService.
+type Ingester interface {
+	IngestEvent(ctx context.Context, req *models.CreateEventRequest, country string) (*models.Event, bool, error)
+}
+
+// New builds the source described by cfg.
+func New(cfg config.SourceConfig) (Source, error) {
+	switch cfg.Type {
+	case "kafka":
+		if len(cfg.Brokers) == 0 || cfg.Topic == "" {
+			return nil, fmt.Errorf("source %q: brokers and topic are required", cfg.Name)
+		}
+		group := cfg.Group
+		if group == "" {
+			group = defaultKafkaGroup
+		}
+		source, err := NewKafkaSource(cfg.Brokers, cfg.Topic, group)
+		if err != nil {
+			return nil, fmt.Errorf("source %q: %w", cfg.Name, err)
+		}
+		return source, nil
+	default:
+		return nil, fmt.Errorf("source %q: type must be kafka", cfg.Name)
+	}
+}
+
+func withDefaults(cfg config.SourceConfig) config.SourceConfig {
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+	return cfg
+}
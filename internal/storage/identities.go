@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// ErrAliasConflict is returned for an ID that is already an alias of a
+// different user, like an anonymous ID shared by two accounts on one device.
+var ErrAliasConflict = errors.New("id is already an alias of another user")
+
+// identityGraph links the IDs events arrive under to the canonical user
+// they belong to. Links are kept flat, every alias pointing straight at its
+// canonical user, so resolving is a single lookup.
+type identityGraph struct {
+	canonical map[string]string
+	aliases   map[string][]string
+}
+
+func newIdentityGraph() identityGraph {
+	return identityGraph{
+		canonical: make(map[string]string),
+		aliases:   make(map[string][]string),
+	}
+}
+
+func (g identityGraph) resolve(id string) string {
+	if canonical, ok := g.canonical[id]; ok {
+		return canonical
+	}
+	return id
+}
+
+// link makes previousID, and any aliases of its own, aliases of userID's
+// canonical user. It returns the canonical user previousID's events were
+// stored under and the one they now belong to, which are equal when the two
+// were already linked.
+func (g identityGraph) link(previousID, userID string) (from, to string, err error) {
+	from, to = g.resolve(previousID), g.resolve(userID)
+	if from == to {
+		return from, to, nil
+	}
+	if from != previousID {
+		return "", "", ErrAliasConflict
+	}
+	for _, alias := range g.aliases[from] {
+		g.canonical[alias] = to
+	}
+	g.aliases[to] = append(g.aliases[to], g.aliases[from]...)
+	g.aliases[to] = append(g.aliases[to], from)
+	delete(g.aliases, from)
+	g.canonical[from] = to
+	return from, to, nil
+}
+
+func (g identityGraph) identity(id string) *models.Identity {
+	canonical := g.resolve(id)
+	aliases := append([]string{}, g.aliases[canonical]...)
+	sort.Strings(aliases)
+	return &models.Identity{UserID: canonical, Aliases: aliases}
+}
@@ -3,40 +3,97 @@ package storage
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 
 	"github.com/dnakolan/event-processing-service/internal/models"
 )
 
+var ErrEventNotFound = errors.New("event not found")
+
 type EventStorage interface {
 	Save(ctx context.Context, Event *models.Event) error
+	// SaveIfAbsent saves Event unless one with its ID is already stored, in
+	// which case the stored event is returned with created false. The check
+	// and the save are atomic.
+	SaveIfAbsent(ctx context.Context, Event *models.Event) (stored *models.Event, created bool, err error)
 	FindAll(ctx context.Context, filter *models.EventFilter) ([]*models.Event, error)
 	FindById(ctx context.Context, uid string) (*models.Event, error)
+	FindByUser(ctx context.Context, userID string) ([]*models.Event, error)
+	Scan(ctx context.Context, filter *models.EventFilter, fn func(*models.Event) error) error
+	// Alias links previousID to userID's canonical user. Events stored
+	// under previousID are moved to that user, as are those saved under it
+	// later, and queries for any of a user's IDs find all their events.
+	Alias(ctx context.Context, previousID, userID string) (*models.Identity, error)
+	Identity(ctx context.Context, id string) (*models.Identity, error)
 	Delete(ctx context.Context, uid string) error
 	Clear(ctx context.Context) error
+	AddObserver(observer EventObserver)
+}
+
+// EventObserver is notified of every change to the store while the write lock
+// is held, so implementations must not block or call back into the storage.
+// Events already stored when the observer is added are replayed to it as
+// saves.
+type EventObserver interface {
+	EventSaved(event, previous *models.Event)
+	EventDeleted(event *models.Event)
+	EventsCleared()
 }
 
 type eventStorage struct {
 	sync.RWMutex
-	data map[string]*models.Event
+	data       map[string]*models.Event
+	byUser     userIndex
+	identities identityGraph
+	observers  []EventObserver
 }
 
 func NewEventStorage() *eventStorage {
 	return &eventStorage{
-		data: make(map[string]*models.Event),
+		data:       make(map[string]*models.Event),
+		byUser:     make(userIndex),
+		identities: newIdentityGraph(),
 	}
 }
 
+// Save stores Event under its user's canonical ID, updating its UserID to
+// match.
 func (s *eventStorage) Save(ctx context.Context, Event *models.Event) error {
 	s.Lock()
 	defer s.Unlock()
-	s.data[Event.EventID] = Event
+	s.save(Event)
 	return nil
 }
 
+func (s *eventStorage) SaveIfAbsent(ctx context.Context, Event *models.Event) (*models.Event, bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if stored, ok := s.data[Event.EventID]; ok {
+		return stored, false, nil
+	}
+	s.save(Event)
+	return Event, true, nil
+}
+
+// save stores Event and notifies observers. Callers hold the write lock.
+func (s *eventStorage) save(Event *models.Event) {
+	Event.UserID = s.identities.resolve(Event.UserID)
+	previous := s.data[Event.EventID]
+	if previous != nil {
+		s.byUser.remove(previous)
+	}
+	s.data[Event.EventID] = Event
+	s.byUser.add(Event)
+	for _, observer := range s.observers {
+		observer.EventSaved(Event, previous)
+	}
+}
+
 func (s *eventStorage) FindAll(ctx context.Context, filter *models.EventFilter) ([]*models.Event, error) {
 	s.RLock()
 	defer s.RUnlock()
+	filter = s.resolveFilter(filter)
 	Events := make([]*models.Event, 0, len(s.data))
 	for _, Event := range s.data {
 		if filter == nil || Event.MatchesFilter(filter) {
@@ -51,15 +108,164 @@ func (s *eventStorage) FindById(ctx context.Context, uid string) (*models.Event,
 	defer s.RUnlock()
 	Event, ok := s.data[uid]
 	if !ok {
-		return nil, errors.New("event not found")
+		return nil, ErrEventNotFound
 	}
 	return Event, nil
 }
 
+// FindByUser returns a user's events in time order without scanning the
+// whole store. userID may be any of the user's IDs.
+func (s *eventStorage) FindByUser(ctx context.Context, userID string) ([]*models.Event, error) {
+	s.RLock()
+	defer s.RUnlock()
+	events := s.byUser[s.identities.resolve(userID)]
+	return append(make([]*models.Event, 0, len(events)), events...), nil
+}
+
+// scanPageSize is how many events Scan gathers each time it takes the lock,
+// and how many users it looks ahead.
+const scanPageSize = 1000
+
+// Scan calls fn with each event matching filter: users in ID order, and each
+// user's events in time order. Events are gathered a page at a time and the
+// lock is only held while a page is, so fn can be slow without holding up
+// writes and memory stays bounded however large the store or its busiest
+// user. Events saved or deleted during a scan may or may not be seen. Scan
+// stops at the first error from fn.
+func (s *eventStorage) Scan(ctx context.Context, filter *models.EventFilter, fn func(*models.Event) error) error {
+	s.RLock()
+	filter = s.resolveFilter(filter)
+	cursor := scanCursor{users: s.scanUsers(filter)}
+	s.RUnlock()
+
+	page := make([]*models.Event, 0, scanPageSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.RLock()
+		page = s.scanPage(filter, &cursor, page[:0])
+		s.RUnlock()
+		for _, event := range page {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if len(cursor.users) == 0 {
+			return nil
+		}
+	}
+}
+
+// scanCursor is where a Scan has got to: the users it has still to visit,
+// the first being the one it is in, and the last event of that user it
+// looked at.
+type scanCursor struct {
+	users []string
+	after *models.Event
+}
+
+// scanPage appends up to a page of the events matching filter after cursor
+// to page, and moves cursor past them. Callers hold the read lock.
+func (s *eventStorage) scanPage(filter *models.EventFilter, cursor *scanCursor, page []*models.Event) []*models.Event {
+	for len(cursor.users) > 0 {
+		events := s.byUser[cursor.users[0]]
+		i := 0
+		if cursor.after != nil {
+			i = sort.Search(len(events), func(i int) bool { return eventBefore(cursor.after, events[i]) })
+		}
+		for ; i < len(events); i++ {
+			cursor.after = events[i]
+			if events[i].MatchesFilter(filter) {
+				page = append(page, events[i])
+				if len(page) == scanPageSize {
+					return page
+				}
+			}
+		}
+		cursor.users = cursor.users[1:]
+		cursor.after = nil
+	}
+	return page
+}
+
+// scanUsers returns the users filter allows, in ID order. Users first seen
+// after a Scan starts aren't visited. Callers hold the read lock.
+func (s *eventStorage) scanUsers(filter *models.EventFilter) []string {
+	if filter != nil && filter.UserID != nil && *filter.UserID != "" {
+		return []string{*filter.UserID}
+	}
+	users := make([]string, 0, len(s.byUser))
+	for user := range s.byUser {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	return users
+}
+
+// resolveFilter returns filter with its user replaced by their canonical
+// user, so filtering by an alias finds the user's whole history. Callers
+// hold the lock.
+func (s *eventStorage) resolveFilter(filter *models.EventFilter) *models.EventFilter {
+	if filter == nil || filter.UserID == nil {
+		return filter
+	}
+	canonical := s.identities.resolve(*filter.UserID)
+	if canonical == *filter.UserID {
+		return filter
+	}
+	resolved := *filter
+	resolved.UserID = &canonical
+	return &resolved
+}
+
+func (s *eventStorage) Alias(ctx context.Context, previousID, userID string) (*models.Identity, error) {
+	s.Lock()
+	defer s.Unlock()
+	from, to, err := s.identities.link(previousID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if from != to {
+		s.reassign(from, to)
+	}
+	return s.identities.identity(to), nil
+}
+
+// reassign moves the events stored under one user to another. Each is
+// replaced by a copy, so observers see the change as any other save.
+// Callers hold the write lock.
+func (s *eventStorage) reassign(from, to string) {
+	for _, event := range append([]*models.Event(nil), s.byUser[from]...) {
+		moved := *event
+		moved.UserID = to
+		s.byUser.remove(event)
+		s.data[moved.EventID] = &moved
+		s.byUser.add(&moved)
+		for _, observer := range s.observers {
+			observer.EventSaved(&moved, event)
+		}
+	}
+}
+
+func (s *eventStorage) Identity(ctx context.Context, id string) (*models.Identity, error) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.identities.identity(id), nil
+}
+
 func (s *eventStorage) Delete(ctx context.Context, uid string) error {
 	s.Lock()
 	defer s.Unlock()
+	Event, ok := s.data[uid]
+	if !ok {
+		return nil
+	}
 	delete(s.data, uid)
+	s.byUser.remove(Event)
+	for _, observer := range s.observers {
+		observer.EventDeleted(Event)
+	}
 	return nil
 }
 
@@ -67,5 +273,19 @@ func (s *eventStorage) Clear(ctx context.Context) error {
 	s.Lock()
 	defer s.Unlock()
 	s.data = make(map[string]*models.Event)
+	s.byUser = make(userIndex)
+	s.identities = newIdentityGraph()
+	for _, observer := range s.observers {
+		observer.EventsCleared()
+	}
 	return nil
 }
+
+func (s *eventStorage) AddObserver(observer EventObserver) {
+	s.Lock()
+	defer s.Unlock()
+	s.observers = append(s.observers, observer)
+	for _, Event := range s.data {
+		observer.EventSaved(Event, nil)
+	}
+}
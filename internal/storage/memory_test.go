@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -38,6 +40,28 @@ func TestEventStorage_Save(t *testing.T) {
 	assert.Equal(t, *event, *saved)
 }
 
+func TestEventStorage_SaveIfAbsent(t *testing.T) {
+	storage := NewEventStorage()
+	ctx := context.Background()
+	zeroTime := time.Unix(0, 0)
+	first := &models.Event{EventID: "1", UserID: "123", EventType: models.EventTypeSignup, Timestamp: &zeroTime}
+	second := &models.Event{EventID: "1", UserID: "456", EventType: models.EventTypeSignup, Timestamp: &zeroTime}
+
+	stored, created, err := storage.SaveIfAbsent(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Same(t, first, stored)
+
+	stored, created, err = storage.SaveIfAbsent(ctx, second)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Same(t, first, stored)
+
+	saved, err := storage.FindById(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "123", saved.UserID)
+}
+
 func TestEventStorage_FindById(t *testing.T) {
 	storage := NewEventStorage()
 	ctx := context.Background()
@@ -217,6 +241,129 @@ func TestEventStorage_FindAll(t *testing.T) {
 	}
 }
 
+func TestEventStorage_FindByUser(t *testing.T) {
+	storage := NewEventStorage()
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	save := func(id, userID string, offset time.Duration) {
+		ts := t0.Add(offset)
+		require.NoError(t, storage.Save(ctx, &models.Event{
+			EventID:   id,
+			UserID:    userID,
+			EventType: models.EventTypeClick,
+			Timestamp: &ts,
+		}))
+	}
+
+	// Saved out of order, and one moved to another user.
+	save("c", "123", 2*time.Minute)
+	save("a", "123", 0)
+	save("b", "123", time.Minute)
+	save("d", "456", time.Minute)
+	save("b", "456", 3*time.Minute)
+	require.NoError(t, storage.Delete(ctx, "a"))
+
+	ids := func(userID string) []string {
+		found, err := storage.FindByUser(ctx, userID)
+		require.NoError(t, err)
+		ids := make([]string, len(found))
+		for i, e := range found {
+			ids[i] = e.EventID
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"c"}, ids("123"))
+	assert.Equal(t, []string{"d", "b"}, ids("456"))
+	assert.Empty(t, ids("789"))
+
+	require.NoError(t, storage.Clear(ctx))
+	assert.Empty(t, ids("456"))
+}
+
+func TestEventStorage_Scan(t *testing.T) {
+	storage := NewEventStorage()
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, user := range []string{"456", "123", "456", "123", "789"} {
+		ts := t0.Add(-time.Duration(i) * time.Minute)
+		require.NoError(t, storage.Save(ctx, &models.Event{
+			EventID:   string(rune('a' + i)),
+			UserID:    user,
+			EventType: models.EventTypeClick,
+			Timestamp: &ts,
+		}))
+	}
+
+	scan := func(filter *models.EventFilter) []string {
+		var ids []string
+		require.NoError(t, storage.Scan(ctx, filter, func(event *models.Event) error {
+			ids = append(ids, event.EventID)
+			return nil
+		}))
+		return ids
+	}
+
+	assert.Equal(t, []string{"d", "b", "c", "a", "e"}, scan(nil))
+	start := t0.Add(-2 * time.Minute)
+	assert.Equal(t, []string{"b", "c", "a"}, scan(&models.EventFilter{StartTimestamp: &start}))
+	user := "456"
+	assert.Equal(t, []string{"c", "a"}, scan(&models.EventFilter{UserID: &user}))
+
+	stop := errors.New("stop")
+	var seen int
+	err := storage.Scan(ctx, nil, func(*models.Event) error {
+		seen++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, seen)
+}
+
+func TestEventStorage_ScanPages(t *testing.T) {
+	storage := NewEventStorage()
+	ctx := context.Background()
+	t0 := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	save := func(id, user string, offset time.Duration) {
+		ts := t0.Add(offset)
+		require.NoError(t, storage.Save(ctx, &models.Event{EventID: id, UserID: user, EventType: models.EventTypeClick, Timestamp: &ts}))
+	}
+
+	// More users than fit in a page, and one user with more events than do.
+	var want []string
+	for i := range scanPageSize + 500 {
+		id := fmt.Sprintf("user-%05d", i)
+		save(id, id, 0)
+		want = append(want, id)
+	}
+	for i := range 2*scanPageSize + 500 {
+		id := fmt.Sprintf("zz-%05d", i)
+		save(id, "zz", time.Duration(i)*time.Second)
+		want = append(want, id)
+	}
+
+	var ids []string
+	require.NoError(t, storage.Scan(ctx, nil, func(event *models.Event) error {
+		ids = append(ids, event.EventID)
+		if len(ids) == 1 {
+			// The lock isn't held while fn runs.
+			save("aa", "aa", 0)
+		}
+		return nil
+	}))
+	assert.Equal(t, want, ids)
+
+	user := "zz"
+	ids = nil
+	require.NoError(t, storage.Scan(ctx, &models.EventFilter{UserID: &user}, func(event *models.Event) error {
+		ids = append(ids, event.EventID)
+		return nil
+	}))
+	assert.Equal(t, want[scanPageSize+500:], ids)
+}
+
 // Helper functions to create pointers
 func float64Ptr(v float64) *float64 {
 	return &v
@@ -225,3 +372,72 @@ func float64Ptr(v float64) *float64 {
 func stringPtr(v string) *string {
 	return &v
 }
+
+type savesObserver struct {
+	saves []*models.Event
+}
+
+func (o *savesObserver) EventSaved(event, previous *models.Event) {
+	if previous != nil {
+		o.saves = append(o.saves, event)
+	}
+}
+func (o *savesObserver) EventDeleted(*models.Event) {}
+func (o *savesObserver) EventsCleared()             {}
+
+func TestEventStorage_Alias(t *testing.T) {
+	storage := NewEventStorage()
+	observer := &savesObserver{}
+	storage.AddObserver(observer)
+	ctx := context.Background()
+	at := func(minute int) *time.Time {
+		timestamp := time.Date(2024, 1, 2, 3, minute, 0, 0, time.UTC)
+		return &timestamp
+	}
+	for _, event := range []*models.Event{
+		{EventID: "1", UserID: "anon-1", EventType: models.EventTypePageView, Timestamp: at(1)},
+		{EventID: "2", UserID: "anon-2", EventType: models.EventTypePageView, Timestamp: at(2)},
+		{EventID: "3", UserID: "alice", EventType: models.EventTypeSignup, Timestamp: at(3)},
+	} {
+		require.NoError(t, storage.Save(ctx, event))
+	}
+
+	identity, err := storage.Alias(ctx, "anon-1", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Identity{UserID: "alice", Aliases: []string{"anon-1"}}, identity)
+	require.Len(t, observer.saves, 1)
+	assert.Equal(t, "alice", observer.saves[0].UserID)
+
+	// anon-2 was already linked to anon-3, whose history comes along.
+	_, err = storage.Alias(ctx, "anon-2", "anon-3")
+	require.NoError(t, err)
+	identity, err = storage.Alias(ctx, "anon-3", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"anon-1", "anon-2", "anon-3"}, identity.Aliases)
+
+	event := &models.Event{EventID: "4", UserID: "anon-2", EventType: models.EventTypePageView, Timestamp: at(4)}
+	require.NoError(t, storage.Save(ctx, event))
+	assert.Equal(t, "alice", event.UserID, "later events are stored under the canonical user")
+
+	for _, id := range []string{"alice", "anon-1", "anon-3"} {
+		events, err := storage.FindByUser(ctx, id)
+		require.NoError(t, err)
+		assert.Len(t, events, 4, id)
+	}
+	user := "anon-1"
+	events, err := storage.FindAll(ctx, &models.EventFilter{UserID: &user})
+	require.NoError(t, err)
+	assert.Len(t, events, 4)
+	scanned := 0
+	require.NoError(t, storage.Scan(ctx, &models.EventFilter{UserID: &user}, func(*models.Event) error {
+		scanned++
+		return nil
+	}))
+	assert.Equal(t, 4, scanned)
+
+	_, err = storage.Alias(ctx, "anon-1", "bob")
+	assert.ErrorIs(t, err, ErrAliasConflict)
+	identity, err = storage.Alias(ctx, "anon-1", "alice")
+	require.NoError(t, err, "linking again is a no-op")
+	assert.Len(t, identity.Aliases, 3)
+}
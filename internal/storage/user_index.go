@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dnakolan/event-processing-service/internal/models"
+)
+
+// userIndex keeps each user's events sorted by timestamp, ties broken by
+// event ID so the order is stable.
+type userIndex map[string][]*models.Event
+
+func (idx userIndex) add(event *models.Event) {
+	events := idx[event.UserID]
+	i := sort.Search(len(events), func(i int) bool { return !eventBefore(events[i], event) })
+	events = append(events, nil)
+	copy(events[i+1:], events[i:])
+	events[i] = event
+	idx[event.UserID] = events
+}
+
+func (idx userIndex) remove(event *models.Event) {
+	events := idx[event.UserID]
+	i := sort.Search(len(events), func(i int) bool { return !eventBefore(events[i], event) })
+	for ; i < len(events); i++ {
+		if events[i] == event {
+			events = append(events[:i], events[i+1:]...)
+			break
+		}
+	}
+	if len(events) == 0 {
+		delete(idx, event.UserID)
+		return
+	}
+	idx[event.UserID] = events
+}
+
+func eventBefore(a, b *models.Event) bool {
+	ta, tb := eventTime(a), eventTime(b)
+	if !ta.Equal(tb) {
+		return ta.Before(tb)
+	}
+	return a.EventID < b.EventID
+}
+
+func eventTime(event *models.Event) time.Time {
+	if event.Timestamp == nil {
+		return time.Time{}
+	}
+	return *event.Timestamp
+}